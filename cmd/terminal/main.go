@@ -1,17 +1,50 @@
 package main
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
 
-	"github.com/ramyad/tucows/internal/api/facade"
+	"github.com/ramyad/tucows/internal/app"
 	"github.com/ramyad/tucows/internal/app/terminal"
+	"github.com/ramyad/tucows/internal/logging"
 )
 
 func main() {
-	api := facade.NewAPIFacade()
-	app := terminal.NewTerminalApp(api)
-	err := app.Run()
+	err := run()
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		// The log may be written to a file, so the error is also reported on standard error.
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(terminal.ExitCode(err))
+	}
+}
+
+func run() error {
+	cfg, err := terminal.LoadConfig(os.Args[1:])
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	logger, closeLog, err := logging.Open(cfg.Log.File, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+	defer closeLog()
+	slog.SetDefault(logger)
+
+	api, _, err := app.NewAPI(cfg, logger)
 	if err != nil {
-		log.Fatalf("Failed to run terminal application: %v", err)
+		return fmt.Errorf("failed to create API: %w", err)
+	}
+	catalog := app.NewCatalog(cfg, logger)
+	app := terminal.NewTerminalApp(api, terminal.WithCatalog(catalog), terminal.WithLogger(logger))
+	if err := app.Run(); err != nil {
+		return fmt.Errorf("failed to run terminal application: %w", err)
 	}
+	return nil
 }
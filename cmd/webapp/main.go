@@ -1,22 +1,68 @@
 package main
 
 import (
+	"errors"
 	"flag"
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
 
-	"github.com/ramyad/tucows/internal/api/facade"
+	"github.com/ramyad/tucows/internal/app"
 	"github.com/ramyad/tucows/internal/app/web"
+	"github.com/ramyad/tucows/internal/config"
+	"github.com/ramyad/tucows/internal/logging"
 )
 
+// configFlags are the configuration settings that can be given as web application flags.
+var configFlags = []string{
+	"log.level", "log.format", "log.file",
+	"web.port", "quote.provider", "image.provider",
+	"resilience.degraded", "resilience.breaker_threshold", "resilience.breaker_cooldown",
+	"resilience.quote_hedge_delay", "resilience.image_hedge_delay",
+	"pairing.prefetch", "pairing.mood", "pairing.mood_candidates", "pairing.keywords",
+}
+
 func main() {
-	port := flag.Int("port", 8080, "Port number for the web application")
-	flag.Parse()
+	err := run()
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		// The log may be written to a file, so the error is also reported on standard error.
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
 
-	api := facade.NewAPIFacade()
-	app := web.NewWebApp(api, *port)
+func run() error {
+	defaults := config.Default()
+	defaults.Image.Width = web.DefaultWebImageWidth
+	defaults.Image.Height = web.DefaultWebImageHeight
+	cfg, err := config.Load(os.Args[1:], config.WithDefaults(defaults), config.WithFlags(configFlags...))
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
 
-	err := app.Run()
+	logger, closeLog, err := logging.Open(cfg.Log.File, cfg.Log.Format, cfg.Log.Level)
 	if err != nil {
-		log.Fatalf("Failed to run terminal application: %v", err)
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+	defer closeLog()
+	slog.SetDefault(logger)
+
+	api, breakers, err := app.NewAPI(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create API: %w", err)
+	}
+	options, err := app.NewOptionsFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	catalog := app.NewCatalog(cfg, logger)
+	app := web.NewWebApp(api, cfg.Web.Port, web.WithBreakers(breakers...), web.WithCatalog(catalog), web.WithDefaults(options), web.WithLogger(logger))
+	if err := app.Run(); err != nil {
+		return fmt.Errorf("failed to run web application: %w", err)
 	}
+	return nil
 }
@@ -1,13 +1,23 @@
 package api
 
 import (
+	"context"
 	"image"
+	"time"
 
-	"github.com/ramyad/tucows/internal/api/imageapi"
-	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/card"
 )
 
+// Pair is a quote and image produced together. Err is set when the pair could not be produced.
+type Pair struct {
+	PairResult
+	Err error
+}
+
 // API represents an interface for interacting with various APIs to fetch random quotes and images.
 type API interface {
-	GetRandomQuoteWithImage(qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) (string, image.Image, error)
+	GetRandomQuoteWithImage(ctx context.Context, req PairRequest) (PairResult, error)
+	GetQuoteCard(ctx context.Context, req PairRequest, cardCnfgBldr *card.CardConfigBuilder) (image.Image, error)
+	GetRandomQuotesWithImages(ctx context.Context, n int, req PairRequest) ([]Pair, error)
+	Subscribe(ctx context.Context, interval time.Duration, req PairRequest) <-chan Pair
 }
@@ -0,0 +1,226 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ramyad/tucows/internal/logging"
+	"github.com/ramyad/tucows/internal/shared"
+)
+
+const (
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = 30 * time.Second
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// Closed lets every call through and counts consecutive failures.
+	Closed State = iota
+	// Open rejects every call until the cooldown has elapsed.
+	Open
+	// HalfOpen lets a single trial call through to decide whether to close or re-open.
+	HalfOpen
+)
+
+// String returns the lowercase name of the state.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// MarshalText encodes the state by its name, so status reports read naturally as JSON.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// OpenError is returned without calling the provider while the breaker rejects calls.
+type OpenError struct {
+	Name       string
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("circuit breaker %q is open, retry after %s", e.Name, e.RetryAfter.Round(time.Second))
+}
+
+// Is classifies the error as shared.ErrUpstreamUnavailable.
+func (e *OpenError) Is(target error) bool {
+	return target == shared.ErrUpstreamUnavailable
+}
+
+// Status is a snapshot of a breaker for logs and status endpoints.
+type Status struct {
+	Name                string     `json:"name"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+}
+
+// BreakerBuilder provides methods for building a Breaker instance.
+type BreakerBuilder struct {
+	breaker *Breaker
+}
+
+// Breaker is a circuit breaker that opens after a number of consecutive failures,
+// rejects calls for a cooldown period and then lets a single trial call through.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreakerBuilder creates a new BreakerBuilder for a breaker identified by name in logs and status reports.
+func NewBreakerBuilder(name string) *BreakerBuilder {
+	return &BreakerBuilder{
+		breaker: &Breaker{
+			name:      name,
+			threshold: DefaultFailureThreshold,
+			cooldown:  DefaultCooldown,
+			now:       time.Now,
+		},
+	}
+}
+
+// WithFailureThreshold sets how many consecutive failures open the breaker and returns the builder instance.
+func (bb *BreakerBuilder) WithFailureThreshold(n int) *BreakerBuilder {
+	bb.breaker.threshold = max(n, 1)
+	return bb
+}
+
+// WithCooldown sets how long the breaker stays open before a trial call and returns the builder instance.
+func (bb *BreakerBuilder) WithCooldown(cooldown time.Duration) *BreakerBuilder {
+	bb.breaker.cooldown = cooldown
+	return bb
+}
+
+// WithLogger sets the logger for state changes and returns the builder instance.
+func (bb *BreakerBuilder) WithLogger(logger *slog.Logger) *BreakerBuilder {
+	bb.breaker.logger = logger
+	return bb
+}
+
+// Build constructs and returns the Breaker.
+func (bb *BreakerBuilder) Build() *Breaker {
+	bb.breaker.logger = logging.OrDefault(bb.breaker.logger).With("breaker", bb.breaker.name)
+	return bb.breaker
+}
+
+// Name returns the name the breaker was built with.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Status returns a snapshot of the breaker state.
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := Status{Name: b.name, State: b.state, ConsecutiveFailures: b.failures}
+	if !b.openedAt.IsZero() {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// Do calls fn unless the breaker is open, and records its outcome. Errors caused by ctx
+// being cancelled or timing out, and invalid input errors, are not held against the provider.
+func (b *Breaker) Do(ctx context.Context, fn func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	if err != nil && (ctx.Err() != nil && errors.Is(err, ctx.Err()) || errors.Is(err, shared.ErrInvalidInput)) {
+		b.release()
+		return err
+	}
+	b.record(err)
+	return err
+}
+
+// allow reports whether a call may go through, moving an open breaker to half-open
+// once the cooldown has elapsed.
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open {
+		if elapsed := b.now().Sub(b.openedAt); elapsed < b.cooldown {
+			return &OpenError{Name: b.name, RetryAfter: b.cooldown - elapsed}
+		}
+		b.transition(HalfOpen)
+	}
+	if b.state == HalfOpen {
+		if b.probing {
+			return &OpenError{Name: b.name}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// release gives up the trial call of a half-open breaker without recording an outcome.
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record updates the breaker with the outcome of a call.
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+
+	if err == nil {
+		b.failures = 0
+		if b.state != Closed {
+			b.transition(Closed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		if b.state != Open {
+			b.transition(Open)
+		}
+	}
+}
+
+// transition changes the state and logs the change. It must be called with mu held.
+func (b *Breaker) transition(state State) {
+	level := slog.LevelInfo
+	if state == Open {
+		level = slog.LevelWarn
+	}
+	b.logger.Log(context.Background(), level, "Circuit breaker changed state", "from", b.state, "to", state, "failures", b.failures)
+	b.state = state
+	if state == Closed {
+		b.openedAt = time.Time{}
+	}
+}
@@ -0,0 +1,120 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ramyad/tucows/internal/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+var errUpstream = errors.New("upstream failed")
+
+// newTestBreaker builds a breaker whose clock only moves when the returned function is called.
+func newTestBreaker(threshold int, cooldown time.Duration) (*Breaker, func(time.Duration)) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewBreakerBuilder("test").WithFailureThreshold(threshold).WithCooldown(cooldown).Build()
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+func fail() error    { return errUpstream }
+func succeed() error { return nil }
+
+func TestBreaker_opensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, b.Do(ctx, fail), errUpstream)
+		assert.Equal(t, Closed, b.Status().State)
+	}
+	assert.ErrorIs(t, b.Do(ctx, fail), errUpstream)
+	assert.Equal(t, Open, b.Status().State)
+
+	called := false
+	err := b.Do(ctx, func() error { called = true; return nil })
+	var openErr *OpenError
+	assert.ErrorAs(t, err, &openErr)
+	assert.Equal(t, time.Minute, openErr.RetryAfter)
+	assert.False(t, called, "Expected the call to be rejected without reaching the provider")
+}
+
+func TestBreaker_successResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(2, time.Minute)
+	ctx := context.Background()
+
+	assert.Error(t, b.Do(ctx, fail))
+	assert.NoError(t, b.Do(ctx, succeed))
+	assert.Error(t, b.Do(ctx, fail))
+	assert.Equal(t, Closed, b.Status().State)
+	assert.Equal(t, 1, b.Status().ConsecutiveFailures)
+}
+
+func TestBreaker_invalidInputIsNotAFailure(t *testing.T) {
+	b, _ := newTestBreaker(1, time.Minute)
+	ctx := context.Background()
+
+	err := b.Do(ctx, func() error { return shared.InvalidInput("image %q not found", "missing") })
+	assert.ErrorIs(t, err, shared.ErrInvalidInput)
+	assert.Equal(t, Closed, b.Status().State)
+	assert.Equal(t, 0, b.Status().ConsecutiveFailures)
+
+	assert.Error(t, b.Do(ctx, fail))
+	var openErr *OpenError
+	err = b.Do(ctx, succeed)
+	assert.ErrorAs(t, err, &openErr)
+	assert.ErrorIs(t, err, shared.ErrUpstreamUnavailable)
+}
+
+func TestBreaker_halfOpenTrialCloses(t *testing.T) {
+	b, advance := newTestBreaker(1, time.Minute)
+	ctx := context.Background()
+
+	assert.Error(t, b.Do(ctx, fail))
+	advance(time.Minute)
+
+	err := b.Do(ctx, func() error {
+		assert.Equal(t, HalfOpen, b.Status().State)
+		var openErr *OpenError
+		assert.ErrorAs(t, b.Do(ctx, succeed), &openErr, "Expected a single trial call in half-open state")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, Closed, b.Status().State)
+	assert.Equal(t, 0, b.Status().ConsecutiveFailures)
+}
+
+func TestBreaker_halfOpenTrialReopens(t *testing.T) {
+	b, advance := newTestBreaker(1, time.Minute)
+	ctx := context.Background()
+
+	assert.Error(t, b.Do(ctx, fail))
+	advance(time.Minute)
+	assert.ErrorIs(t, b.Do(ctx, fail), errUpstream)
+	assert.Equal(t, Open, b.Status().State)
+
+	advance(30 * time.Second)
+	var openErr *OpenError
+	assert.ErrorAs(t, b.Do(ctx, succeed), &openErr)
+	assert.Equal(t, 30*time.Second, openErr.RetryAfter)
+}
+
+func TestBreaker_ignoresCallerCancellation(t *testing.T) {
+	b, _ := newTestBreaker(1, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := b.Do(ctx, func() error { return ctx.Err() })
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, Closed, b.Status().State)
+	assert.Equal(t, 0, b.Status().ConsecutiveFailures)
+}
+
+func TestState_String(t *testing.T) {
+	assert.Equal(t, "closed", Closed.String())
+	assert.Equal(t, "open", Open.String())
+	assert.Equal(t, "half-open", HalfOpen.String())
+}
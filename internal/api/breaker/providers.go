@@ -0,0 +1,104 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"image"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+)
+
+// quoteProvider guards a QuoteProvider with a Breaker.
+type quoteProvider struct {
+	inner   quoteapi.QuoteProvider
+	breaker *Breaker
+}
+
+// WrapQuoteProvider returns a QuoteProvider that fails fast with an OpenError while the
+// breaker is open. Author quotes are used when the wrapped provider supports them.
+func WrapQuoteProvider(inner quoteapi.QuoteProvider, breaker *Breaker) quoteapi.QuoteProvider {
+	return &quoteProvider{inner: inner, breaker: breaker}
+}
+
+// GetRandomQuote fetches a random quote from the wrapped provider through the breaker.
+func (p *quoteProvider) GetRandomQuote(ctx context.Context, qtCnfgBldr *quoteapi.QuoteConfigBuilder) (string, error) {
+	quote, err := p.GetRandomQuoteWithAuthor(ctx, qtCnfgBldr)
+	return quote.Text, err
+}
+
+// GetRandomQuoteWithAuthor fetches a random quote and its author from the wrapped provider through the breaker.
+func (p *quoteProvider) GetRandomQuoteWithAuthor(ctx context.Context, qtCnfgBldr *quoteapi.QuoteConfigBuilder) (quoteapi.Quote, error) {
+	var quote quoteapi.Quote
+	err := p.breaker.Do(ctx, func() error {
+		if authored, ok := p.inner.(quoteapi.AuthorQuoteProvider); ok {
+			var err error
+			quote, err = authored.GetRandomQuoteWithAuthor(ctx, qtCnfgBldr)
+			return err
+		}
+		text, err := p.inner.GetRandomQuote(ctx, qtCnfgBldr)
+		quote = quoteapi.Quote{Text: text}
+		return err
+	})
+	if err != nil {
+		return quoteapi.Quote{}, err
+	}
+	return quote, nil
+}
+
+// imageProvider guards an ImageProvider with a Breaker.
+type imageProvider struct {
+	inner   imageapi.ImageProvider
+	breaker *Breaker
+}
+
+// searchImageProvider guards an ImageProvider that also implements imageapi.ImageSearcher.
+type searchImageProvider struct {
+	imageProvider
+	searcher imageapi.ImageSearcher
+}
+
+// WrapImageProvider returns an ImageProvider that fails fast with an OpenError while the
+// breaker is open. Search is kept when the wrapped provider supports it.
+func WrapImageProvider(inner imageapi.ImageProvider, breaker *Breaker) imageapi.ImageProvider {
+	if searcher, ok := inner.(imageapi.ImageSearcher); ok {
+		return &searchImageProvider{imageProvider: imageProvider{inner: inner, breaker: breaker}, searcher: searcher}
+	}
+	return &imageProvider{inner: inner, breaker: breaker}
+}
+
+// GetRandomImage fetches a random image from the wrapped provider through the breaker.
+func (p *imageProvider) GetRandomImage(ctx context.Context, imgCnfg *imageapi.ImageConfigBuilder) (image.Image, error) {
+	var img image.Image
+	err := p.breaker.Do(ctx, func() error {
+		var err error
+		img, err = p.inner.GetRandomImage(ctx, imgCnfg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// SearchImage searches the wrapped provider through the breaker. Finding no match is not a failure.
+func (p *searchImageProvider) SearchImage(ctx context.Context, imgCnfg *imageapi.ImageConfigBuilder) (image.Image, error) {
+	var img image.Image
+	var noMatch bool
+	err := p.breaker.Do(ctx, func() error {
+		var err error
+		img, err = p.searcher.SearchImage(ctx, imgCnfg)
+		if errors.Is(err, imageapi.ErrNoSearchMatch) {
+			noMatch = true
+			return nil
+		}
+		return err
+	})
+	if noMatch {
+		return nil, imageapi.ErrNoSearchMatch
+	}
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
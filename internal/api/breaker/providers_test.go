@@ -0,0 +1,79 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingImageProvider counts calls and always fails.
+type failingImageProvider struct {
+	calls int
+}
+
+func (p *failingImageProvider) GetRandomImage(ctx context.Context, imgCnfg *imageapi.ImageConfigBuilder) (image.Image, error) {
+	p.calls++
+	return nil, errors.New("image failed")
+}
+
+func TestWrapImageProvider_failsFastWhenOpen(t *testing.T) {
+	inner := &failingImageProvider{}
+	provider := WrapImageProvider(inner, NewBreakerBuilder("image").WithFailureThreshold(2).Build())
+
+	for i := 0; i < 4; i++ {
+		_, err := provider.GetRandomImage(context.Background(), imageapi.NewImageConfigBuilder())
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, inner.calls, "Expected calls to stop reaching the provider once the breaker opened")
+}
+
+func TestWrapQuoteProvider_keepsAuthors(t *testing.T) {
+	inner, err := quoteapi.NewStaticQuoteProvider([]quoteapi.Quote{{Text: "Stay hungry.", Author: "Steve Jobs"}})
+	assert.NoError(t, err)
+	provider := WrapQuoteProvider(inner, NewBreakerBuilder("quote").Build())
+
+	authored, ok := provider.(quoteapi.AuthorQuoteProvider)
+	assert.True(t, ok, "Expected the wrapped provider to support authors")
+	quote, err := authored.GetRandomQuoteWithAuthor(context.Background(), quoteapi.NewQuoteConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, quoteapi.Quote{Text: "Stay hungry.", Author: "Steve Jobs"}, quote)
+
+	text, err := provider.GetRandomQuote(context.Background(), quoteapi.NewQuoteConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, "Stay hungry.", text)
+}
+
+// searchingImageProvider finds images for the keyword "match" only.
+type searchingImageProvider struct {
+	failingImageProvider
+}
+
+func (p *searchingImageProvider) SearchImage(ctx context.Context, imgCnfg *imageapi.ImageConfigBuilder) (image.Image, error) {
+	if len(imgCnfg.Build().Keywords) > 0 && imgCnfg.Build().Keywords[0] == "match" {
+		return image.NewRGBA(image.Rect(0, 0, 1, 1)), nil
+	}
+	return nil, imageapi.ErrNoSearchMatch
+}
+
+func TestWrapImageProvider_keepsSearch(t *testing.T) {
+	b := NewBreakerBuilder("image").WithFailureThreshold(1).Build()
+	provider := WrapImageProvider(&searchingImageProvider{}, b)
+	searcher, ok := provider.(imageapi.ImageSearcher)
+	assert.True(t, ok, "Expected the wrapped provider to support search")
+
+	_, err := searcher.SearchImage(context.Background(), imageapi.NewImageConfigBuilder().WithKeywords([]string{"other"}))
+	assert.ErrorIs(t, err, imageapi.ErrNoSearchMatch)
+	assert.Equal(t, Closed, b.Status().State, "Expected no match not to count as a failure")
+
+	img, err := searcher.SearchImage(context.Background(), imageapi.NewImageConfigBuilder().WithKeywords([]string{"match"}))
+	assert.NoError(t, err)
+	assert.NotNil(t, img)
+
+	_, ok = WrapImageProvider(&failingImageProvider{}, b).(imageapi.ImageSearcher)
+	assert.False(t, ok, "Expected providers without search to stay without search")
+}
@@ -0,0 +1,123 @@
+package facade
+
+import (
+	"context"
+	"image"
+	"sync"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/shared"
+)
+
+const (
+	DefaultBatchConcurrency = 4
+	DefaultBatchRefetches   = 2
+	MaxBatchSize            = 50
+)
+
+// WithBatchConcurrency sets how many pairs GetRandomQuotesWithImages fetches at the same time.
+func WithBatchConcurrency(n int) Option {
+	return func(facade *APIFacade) {
+		facade.batchConcurrency = max(n, 1)
+	}
+}
+
+// WithBatchRefetches sets how many extra fetches may be spent replacing a duplicate pair in a batch.
+func WithBatchRefetches(n int) Option {
+	return func(facade *APIFacade) {
+		facade.batchRefetches = max(n, 0)
+	}
+}
+
+// GetRandomQuotesWithImages fetches n quote/image pairs with bounded concurrency, all sharing
+// the deadline of ctx. Pairs repeating a quote or a near-duplicate image of another pair in the
+// batch are re-fetched within the refetch budget. Failures are reported per pair in Pair.Err;
+// the returned error is only set for an invalid batch size.
+func (facade *APIFacade) GetRandomQuotesWithImages(ctx context.Context, n int, req api.PairRequest) ([]api.Pair, error) {
+	if n < 1 || n > MaxBatchSize {
+		return nil, shared.InvalidInput("batch size must be between 1 and %d, got %d", MaxBatchSize, n)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	qtcnfbldr, imgCnfgBldr := req.QuoteConfigBuilder(), req.ImageConfigBuilder()
+
+	pairs := make([]api.Pair, n)
+	seen := &batchHistory{quotes: map[string]bool{}}
+	semaphore := make(chan struct{}, max(facade.batchConcurrency, 1))
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				pairs[i] = api.Pair{Err: ctx.Err()}
+				return
+			}
+			pairs[i] = facade.fetchUniquePair(ctx, qtcnfbldr, imgCnfgBldr, seen)
+		}(i)
+	}
+	wg.Wait()
+
+	return pairs, nil
+}
+
+// fetchUniquePair fetches a pair, re-fetching it while it duplicates a pair already in the batch.
+func (facade *APIFacade) fetchUniquePair(ctx context.Context, qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder, seen *batchHistory) api.Pair {
+	for attempt := 0; ; attempt++ {
+		quote, image, err := facade.fetchQuoteAndImage(ctx, qtcnfbldr, imgCnfgBldr)
+		if err != nil {
+			return api.Pair{Err: err}
+		}
+
+		pair := api.Pair{PairResult: api.PairResult{Quote: quote.Text, Author: quote.Author, Image: image}}
+		if seen.claim(quote.Text, imageHash(image)) {
+			return pair
+		}
+		if attempt >= facade.batchRefetches {
+			facade.logger.WarnContext(ctx, "Keeping duplicate pair after refetches", "refetches", attempt)
+			return pair
+		}
+	}
+}
+
+// imageHash returns the perceptual hash stored in the image metadata, computing it if absent.
+func imageHash(img image.Image) uint64 {
+	if metadata, ok := imageapi.MetadataOf(img); ok && metadata.Hash != 0 {
+		return metadata.Hash
+	}
+	return imageapi.DifferenceHash(img)
+}
+
+// batchHistory records the quotes and image hashes already claimed by pairs of a batch.
+type batchHistory struct {
+	mu     sync.Mutex
+	quotes map[string]bool
+	hashes []uint64
+}
+
+// claim reports whether neither the quote nor an image similar to hash was claimed
+// before, and records both if so.
+func (h *batchHistory) claim(quote string, hash uint64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	unique := !h.quotes[quote]
+	for _, seen := range h.hashes {
+		if imageapi.HammingDistance(hash, seen) <= imageapi.DefaultDedupThreshold {
+			unique = false
+			break
+		}
+	}
+	if unique {
+		h.quotes[quote] = true
+		h.hashes = append(h.hashes, hash)
+	}
+	return unique
+}
@@ -0,0 +1,130 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// sequenceQuoteProvider returns the given quotes in order, cycling when exhausted,
+// and records the highest number of concurrent calls.
+type sequenceQuoteProvider struct {
+	mu            sync.Mutex
+	quotes        []string
+	calls         int
+	active        int32
+	maxActive     int32
+	delay         time.Duration
+	failFromIndex int
+}
+
+func (p *sequenceQuoteProvider) GetRandomQuote(ctx context.Context, qc *quoteapi.QuoteConfigBuilder) (string, error) {
+	active := atomic.AddInt32(&p.active, 1)
+	defer atomic.AddInt32(&p.active, -1)
+	for {
+		maxActive := atomic.LoadInt32(&p.maxActive)
+		if active <= maxActive || atomic.CompareAndSwapInt32(&p.maxActive, maxActive, active) {
+			break
+		}
+	}
+	time.Sleep(p.delay)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	index := p.calls
+	p.calls++
+	if p.failFromIndex > 0 && index >= p.failFromIndex {
+		return "", fmt.Errorf("quote %d failed", index)
+	}
+	return p.quotes[index%len(p.quotes)], nil
+}
+
+// noiseImageProvider returns a fresh random noise image on every call, so that no two images
+// are perceptually similar.
+type noiseImageProvider struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func (p *noiseImageProvider) GetRandomImage(ctx context.Context, imgCnfg *imageapi.ImageConfigBuilder) (image.Image, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rng == nil {
+		p.rng = rand.New(rand.NewSource(1))
+	}
+	img := image.NewGray(image.Rect(0, 0, 32, 32))
+	p.rng.Read(img.Pix)
+	return img, nil
+}
+
+func TestGetRandomQuotesWithImages_success(t *testing.T) {
+	quotes := &sequenceQuoteProvider{quotes: []string{"one", "two", "three", "four", "five"}, delay: 10 * time.Millisecond}
+	apiFacade := NewAPIFacade(WithQuoteProvider(quotes), WithImageProvider(&noiseImageProvider{}), WithBatchConcurrency(2))
+
+	pairs, err := apiFacade.GetRandomQuotesWithImages(context.Background(), 5, newRequest(t, imageapi.NewImageConfigBuilder().WithWidth(32).WithHeight(32)))
+	assert.NoError(t, err)
+	assert.Len(t, pairs, 5)
+
+	seen := map[string]bool{}
+	for _, pair := range pairs {
+		assert.NoError(t, pair.Err)
+		assert.NotNil(t, pair.Image)
+		assert.False(t, seen[pair.Quote], "Expected unique quotes")
+		seen[pair.Quote] = true
+	}
+	assert.LessOrEqual(t, atomic.LoadInt32(&quotes.maxActive), int32(2), "Expected concurrency to be bounded")
+}
+
+func TestGetRandomQuotesWithImages_refetchesDuplicateQuotes(t *testing.T) {
+	quotes := &sequenceQuoteProvider{quotes: []string{"same", "same", "other"}}
+	apiFacade := NewAPIFacade(WithQuoteProvider(quotes), WithImageProvider(&noiseImageProvider{}), WithBatchConcurrency(1))
+
+	pairs, err := apiFacade.GetRandomQuotesWithImages(context.Background(), 2, newRequest(t, imageapi.NewImageConfigBuilder().WithWidth(32).WithHeight(32)))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"same", "other"}, []string{pairs[0].Quote, pairs[1].Quote})
+	assert.Equal(t, 3, quotes.calls)
+}
+
+func TestGetRandomQuotesWithImages_perItemErrors(t *testing.T) {
+	quotes := &sequenceQuoteProvider{quotes: []string{"one", "two", "three"}, failFromIndex: 2}
+	apiFacade := NewAPIFacade(WithQuoteProvider(quotes), WithImageProvider(&noiseImageProvider{}), WithBatchConcurrency(1))
+
+	pairs, err := apiFacade.GetRandomQuotesWithImages(context.Background(), 3, newRequest(t, imageapi.NewImageConfigBuilder().WithWidth(32).WithHeight(32)))
+	assert.NoError(t, err)
+	failed := 0
+	for _, pair := range pairs {
+		if pair.Err != nil {
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed, "Expected exactly one failed pair")
+}
+
+func TestGetRandomQuotesWithImages_sharedDeadline(t *testing.T) {
+	apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"one"}}), WithImageProvider(blockingImageProvider{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	pairs, err := apiFacade.GetRandomQuotesWithImages(ctx, 3, newRequest(t, imageapi.NewImageConfigBuilder()))
+	assert.NoError(t, err)
+	for _, pair := range pairs {
+		assert.ErrorIs(t, pair.Err, context.DeadlineExceeded)
+	}
+}
+
+func TestGetRandomQuotesWithImages_invalidSize(t *testing.T) {
+	apiFacade := NewAPIFacade()
+	_, err := apiFacade.GetRandomQuotesWithImages(context.Background(), 0, newRequest(t, imageapi.NewImageConfigBuilder()))
+	assert.Error(t, err)
+	_, err = apiFacade.GetRandomQuotesWithImages(context.Background(), MaxBatchSize+1, newRequest(t, imageapi.NewImageConfigBuilder()))
+	assert.Error(t, err)
+}
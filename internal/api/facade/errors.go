@@ -0,0 +1,31 @@
+package facade
+
+import (
+	"fmt"
+)
+
+// QuoteFetchError is returned when the quote provider fails to return a quote.
+type QuoteFetchError struct {
+	Err error
+}
+
+func (e *QuoteFetchError) Error() string {
+	return fmt.Sprintf("error calling quote api: %v", e.Err)
+}
+
+func (e *QuoteFetchError) Unwrap() error {
+	return e.Err
+}
+
+// ImageFetchError is returned when the image provider fails to return an image.
+type ImageFetchError struct {
+	Err error
+}
+
+func (e *ImageFetchError) Error() string {
+	return fmt.Sprintf("error calling image api: %v", e.Err)
+}
+
+func (e *ImageFetchError) Unwrap() error {
+	return e.Err
+}
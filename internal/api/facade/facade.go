@@ -1,15 +1,29 @@
 package facade
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
+	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/ramyad/tucows/internal/api"
 	"github.com/ramyad/tucows/internal/api/imageapi"
 	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/card"
+	"github.com/ramyad/tucows/internal/logging"
 )
 
+// DefaultFallbackQuote is returned in degraded mode when the quote provider fails.
+var DefaultFallbackQuote = quoteapi.Quote{Text: "The best way out is always through.", Author: "Robert Frost"}
+
+// DefaultPlaceholderColor fills the placeholder image returned in degraded mode when the image provider fails.
+var DefaultPlaceholderColor = color.RGBA{R: 96, G: 96, B: 96, A: 255}
+
 // Ensure that *APIFacade implements api.API interface
 var _ api.API = (*APIFacade)(nil)
 
@@ -17,44 +31,216 @@ var _ api.API = (*APIFacade)(nil)
 type APIFacade struct {
 	quoteProvider quoteapi.QuoteProvider
 	imageProvider imageapi.ImageProvider
+	logger        *slog.Logger
+
+	degraded         bool
+	fallbackQuote    quoteapi.Quote
+	placeholderImage image.Image
+
+	batchConcurrency int
+	batchRefetches   int
+
+	quoteHedgeDelay time.Duration
+	imageHedgeDelay time.Duration
+	quoteHedges     hedgeCounters
+	imageHedges     hedgeCounters
+
+	pool *prefetchPool
+
+	moodFilters    bool
+	moodCandidates int
+
+	searchKeywords int
+}
+
+// Option configures optional behaviour of the APIFacade.
+type Option func(*APIFacade)
+
+// WithQuoteProvider replaces the default forismatic quote provider.
+func WithQuoteProvider(provider quoteapi.QuoteProvider) Option {
+	return func(facade *APIFacade) {
+		facade.quoteProvider = provider
+	}
+}
+
+// WithImageProvider replaces the default picsum image provider.
+func WithImageProvider(provider imageapi.ImageProvider) Option {
+	return func(facade *APIFacade) {
+		facade.imageProvider = provider
+	}
+}
+
+// WithLogger sets the logger for degraded results, hedged requests and prefetching.
+// By default slog.Default() is used.
+func WithLogger(logger *slog.Logger) Option {
+	return func(facade *APIFacade) {
+		facade.logger = logger
+	}
+}
+
+// WithDegradedMode makes the facade return partial results when exactly one provider fails:
+// the quote with a placeholder image, or the image with a fallback quote.
+// Without it, a failure of either provider cancels the other and fails the whole request.
+func WithDegradedMode() Option {
+	return func(facade *APIFacade) {
+		facade.degraded = true
+	}
+}
+
+// WithFallbackQuote sets the quote returned in degraded mode when the quote provider fails.
+func WithFallbackQuote(quote quoteapi.Quote) Option {
+	return func(facade *APIFacade) {
+		facade.fallbackQuote = quote
+	}
+}
+
+// WithPlaceholderImage sets the image returned in degraded mode when the image provider fails.
+// By default a solid image of the requested size is used.
+func WithPlaceholderImage(img image.Image) Option {
+	return func(facade *APIFacade) {
+		facade.placeholderImage = img
+	}
 }
 
 // NewAPIFacade creates a new instance of API interface.
-func NewAPIFacade() api.API {
-	return &APIFacade{
-		quoteProvider: quoteapi.NewQuoteApiBuilder().Build(),
-		imageProvider: imageapi.NewImageAPIBuilder().Build(),
+func NewAPIFacade(opts ...Option) api.API {
+	facade := &APIFacade{
+		quoteProvider:    quoteapi.NewQuoteApiBuilder().Build(),
+		imageProvider:    imageapi.NewDedupImageProviderBuilder(imageapi.NewImageAPIBuilder().Build()).Build(),
+		fallbackQuote:    DefaultFallbackQuote,
+		batchConcurrency: DefaultBatchConcurrency,
+		batchRefetches:   DefaultBatchRefetches,
+	}
+	for _, opt := range opts {
+		opt(facade)
 	}
+	facade.logger = logging.OrDefault(facade.logger)
+	return facade
 }
 
-// GetRandomQuoteWithImage fetches a random quote and image concurrently using the provided configurations.
-// It returns the fetched quote, image, and any error encountered during the fetching process.
-func (facade *APIFacade) GetRandomQuoteWithImage(qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) (string, image.Image, error) {
+// GetRandomQuoteWithImage fetches a random quote and image concurrently as described by the request.
+// It returns the fetched pair, or any error encountered during the fetching process.
+func (facade *APIFacade) GetRandomQuoteWithImage(ctx context.Context, req api.PairRequest) (api.PairResult, error) {
+	if err := req.Validate(); err != nil {
+		return api.PairResult{}, err
+	}
+	quote, image, err := facade.nextPair(ctx, req.QuoteConfigBuilder(), req.ImageConfigBuilder())
+	if err != nil {
+		return api.PairResult{}, err
+	}
+	return api.PairResult{Quote: quote.Text, Author: quote.Author, Image: image}, nil
+}
+
+// GetQuoteCard fetches a random quote and image concurrently and renders the quote
+// and its author over the image according to the card configuration.
+func (facade *APIFacade) GetQuoteCard(ctx context.Context, req api.PairRequest, cardCnfgBldr *card.CardConfigBuilder) (image.Image, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	quote, image, err := facade.nextPair(ctx, req.QuoteConfigBuilder(), req.ImageConfigBuilder())
+	if err != nil {
+		return nil, err
+	}
+
+	cardImage, err := card.Render(image, quote.Text, quote.Author, cardCnfgBldr)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering quote card: %w", err)
+	}
+	return cardImage, nil
+}
+
+// fetchQuoteAndImage fetches a random quote and image concurrently. Failures are reported
+// as a QuoteFetchError and/or ImageFetchError combined with errors.Join. Outside degraded
+// mode the first failure cancels the sibling request, whose cancellation is not reported.
+func (facade *APIFacade) fetchQuoteAndImage(ctx context.Context, qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) (quoteapi.Quote, image.Image, error) {
 	var wg sync.WaitGroup
-	var quote string
-	var image image.Image
+	var quote quoteapi.Quote
+	var img image.Image
 	var quoteErr, imageErr error
 
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	quoteDone := make(chan struct{})
+	awaitQuote := func() quoteapi.Quote {
+		<-quoteDone
+		return quote
+	}
+
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		quote, quoteErr = facade.quoteProvider.GetRandomQuote(qtcnfbldr)
+		defer close(quoteDone)
+		quote, quoteErr = hedge(fetchCtx, facade.logger, "Quote", facade.quoteHedgeDelay, &facade.quoteHedges, func(ctx context.Context) (quoteapi.Quote, error) {
+			return facade.getRandomQuote(ctx, qtcnfbldr)
+		})
+		if quoteErr != nil && !facade.degraded {
+			cancel()
+		}
 	}()
 
 	go func() {
 		defer wg.Done()
-		image, imageErr = facade.imageProvider.GetRandomImage(imgCnfgBldr)
+		img, imageErr = facade.getImage(fetchCtx, imgCnfgBldr, awaitQuote)
+		if imageErr != nil && !facade.degraded {
+			cancel()
+		}
 	}()
 
 	wg.Wait()
 
-	if quoteErr != nil {
-		return "", nil, fmt.Errorf("error calling quote api: %w", quoteErr)
+	if ctx.Err() == nil {
+		quoteErr = ignoreSiblingCancellation(quoteErr, imageErr)
+		imageErr = ignoreSiblingCancellation(imageErr, quoteErr)
 	}
 
-	if imageErr != nil {
-		return "", nil, fmt.Errorf("error calling quote api: %w", imageErr)
+	switch {
+	case quoteErr != nil && imageErr != nil:
+		return quoteapi.Quote{}, nil, errors.Join(&QuoteFetchError{Err: quoteErr}, &ImageFetchError{Err: imageErr})
+	case quoteErr != nil && facade.degraded:
+		facade.logger.WarnContext(ctx, "Quote provider failed, using fallback quote", logging.Err(quoteErr))
+		return facade.fallbackQuote, img, nil
+	case quoteErr != nil:
+		return quoteapi.Quote{}, nil, &QuoteFetchError{Err: quoteErr}
+	case imageErr != nil && facade.degraded:
+		facade.logger.WarnContext(ctx, "Image provider failed, using placeholder image", logging.Err(imageErr))
+		return quote, facade.placeholder(imgCnfgBldr), nil
+	case imageErr != nil:
+		return quoteapi.Quote{}, nil, &ImageFetchError{Err: imageErr}
 	}
 
-	return quote, image, nil
+	return quote, img, nil
+}
+
+// ignoreSiblingCancellation drops err when it only reports the cancellation
+// triggered by the sibling request failing with siblingErr.
+func ignoreSiblingCancellation(err, siblingErr error) error {
+	if siblingErr != nil && errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// placeholder returns the configured placeholder image or a solid image of the requested size.
+func (facade *APIFacade) placeholder(imgCnfgBldr *imageapi.ImageConfigBuilder) image.Image {
+	if facade.placeholderImage != nil {
+		return facade.placeholderImage
+	}
+	config := imgCnfgBldr.Build()
+	img := image.NewRGBA(image.Rect(0, 0, max(config.Width, 1), max(config.Height, 1)))
+	draw.Draw(img, img.Bounds(), &image.Uniform{DefaultPlaceholderColor}, image.Point{}, draw.Src)
+	return img
+}
+
+// getRandomQuote fetches a random quote, including its author when the provider supports it.
+func (facade *APIFacade) getRandomQuote(ctx context.Context, qtcnfbldr *quoteapi.QuoteConfigBuilder) (quoteapi.Quote, error) {
+	if authored, ok := facade.quoteProvider.(quoteapi.AuthorQuoteProvider); ok {
+		return authored.GetRandomQuoteWithAuthor(ctx, qtcnfbldr)
+	}
+
+	text, err := facade.quoteProvider.GetRandomQuote(ctx, qtcnfbldr)
+	if err != nil {
+		return quoteapi.Quote{}, err
+	}
+	return quoteapi.Quote{Text: text}, nil
 }
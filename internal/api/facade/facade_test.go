@@ -1,12 +1,16 @@
 package facade
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"image"
 	"testing"
 
+	"github.com/ramyad/tucows/internal/api"
 	"github.com/ramyad/tucows/internal/api/imageapi"
 	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/card"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -15,7 +19,7 @@ type MockQuoteProvider struct {
 	mock.Mock
 }
 
-func (m *MockQuoteProvider) GetRandomQuote(qc *quoteapi.QuoteConfigBuilder) (string, error) {
+func (m *MockQuoteProvider) GetRandomQuote(ctx context.Context, qc *quoteapi.QuoteConfigBuilder) (string, error) {
 	args := m.Called(qc)
 	return args.String(0), args.Error(1)
 }
@@ -24,7 +28,7 @@ type MockImageProvider struct {
 	mock.Mock
 }
 
-func (m *MockImageProvider) GetRandomImage(ic *imageapi.ImageConfigBuilder) (image.Image, error) {
+func (m *MockImageProvider) GetRandomImage(ctx context.Context, ic *imageapi.ImageConfigBuilder) (image.Image, error) {
 	args := m.Called(ic)
 	return args.Get(0).(image.Image), args.Error(1)
 }
@@ -38,12 +42,12 @@ func TestGetRandomQuoteWithImage_success(t *testing.T) {
 	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("Random Quote", nil)
 	mockImageProvider.On("GetRandomImage", mock.Anything).Return(mockImage, nil)
 
-	apiFacade := APIFacade{
+	apiFacade := &APIFacade{
 		quoteProvider: mockQuoteProvider,
 		imageProvider: mockImageProvider,
 	}
 
-	quote, image, err := apiFacade.GetRandomQuoteWithImage(quoteapi.NewQuoteConfigBuilder(), imageapi.NewImageConfigBuilder())
+	quote, image, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
 	assert.NoError(t, err)
 	assert.Equal(t, "Random Quote", quote)
 	assert.Equal(t, mockImage, image)
@@ -58,12 +62,12 @@ func TestGetRandomQuoteWithImage_quoteProviderReturnError(t *testing.T) {
 	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("", fmt.Errorf("fetch quote failed"))
 	mockImageProvider.On("GetRandomImage", mock.Anything).Return(mockImage, nil)
 
-	apiFacade := APIFacade{
+	apiFacade := &APIFacade{
 		quoteProvider: mockQuoteProvider,
 		imageProvider: mockImageProvider,
 	}
 
-	_, _, err := apiFacade.GetRandomQuoteWithImage(quoteapi.NewQuoteConfigBuilder(), imageapi.NewImageConfigBuilder())
+	_, _, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
 	assert.Error(t, err, fmt.Errorf("fetch quote failed"))
 }
 
@@ -76,11 +80,170 @@ func TestGetRandomQuoteWithImage_imageProviderReturnError(t *testing.T) {
 	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("Random Quote", nil)
 	mockImageProvider.On("GetRandomImage", mock.Anything).Return(mockImage, fmt.Errorf("fetch image failed"))
 
-	apiFacade := APIFacade{
+	apiFacade := &APIFacade{
 		quoteProvider: mockQuoteProvider,
 		imageProvider: mockImageProvider,
 	}
 
-	_, _, err := apiFacade.GetRandomQuoteWithImage(quoteapi.NewQuoteConfigBuilder(), imageapi.NewImageConfigBuilder())
+	_, _, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
 	assert.Error(t, err, fmt.Errorf("fetch image failed"))
 }
+
+type MockAuthorQuoteProvider struct {
+	MockQuoteProvider
+}
+
+func (m *MockAuthorQuoteProvider) GetRandomQuoteWithAuthor(ctx context.Context, qc *quoteapi.QuoteConfigBuilder) (quoteapi.Quote, error) {
+	args := m.Called(qc)
+	return args.Get(0).(quoteapi.Quote), args.Error(1)
+}
+
+func TestGetQuoteCard_success(t *testing.T) {
+
+	mockQuoteProvider := new(MockAuthorQuoteProvider)
+	mockImageProvider := new(MockImageProvider)
+	mockImage := image.NewRGBA(image.Rect(0, 0, 200, 100))
+
+	mockQuoteProvider.On("GetRandomQuoteWithAuthor", mock.Anything).Return(quoteapi.Quote{Text: "Random Quote", Author: "Someone"}, nil)
+	mockImageProvider.On("GetRandomImage", mock.Anything).Return(mockImage, nil)
+
+	apiFacade := &APIFacade{
+		quoteProvider: mockQuoteProvider,
+		imageProvider: mockImageProvider,
+	}
+
+	cardImage, err := apiFacade.GetQuoteCard(context.Background(), newRequest(t, imageapi.NewImageConfigBuilder()), card.NewCardConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, mockImage.Bounds(), cardImage.Bounds())
+	mockQuoteProvider.AssertNotCalled(t, "GetRandomQuote", mock.Anything)
+}
+
+func TestGetQuoteCard_imageProviderReturnError(t *testing.T) {
+
+	mockQuoteProvider := new(MockQuoteProvider)
+	mockImageProvider := new(MockImageProvider)
+
+	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("Random Quote", nil)
+	mockImageProvider.On("GetRandomImage", mock.Anything).Return(image.NewRGBA(image.Rect(0, 0, 0, 0)), fmt.Errorf("fetch image failed"))
+
+	apiFacade := &APIFacade{
+		quoteProvider: mockQuoteProvider,
+		imageProvider: mockImageProvider,
+	}
+
+	_, err := apiFacade.GetQuoteCard(context.Background(), newRequest(t, imageapi.NewImageConfigBuilder()), card.NewCardConfigBuilder())
+	assert.Error(t, err)
+}
+
+// blockingImageProvider blocks until its context is cancelled.
+type blockingImageProvider struct{}
+
+func (blockingImageProvider) GetRandomImage(ctx context.Context, ic *imageapi.ImageConfigBuilder) (image.Image, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestGetRandomQuoteWithImage_bothProvidersReturnError(t *testing.T) {
+
+	mockQuoteProvider := new(MockQuoteProvider)
+	mockImageProvider := new(MockImageProvider)
+
+	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("", fmt.Errorf("fetch quote failed"))
+	mockImageProvider.On("GetRandomImage", mock.Anything).Return(image.NewRGBA(image.Rect(0, 0, 0, 0)), fmt.Errorf("fetch image failed"))
+
+	apiFacade := &APIFacade{
+		quoteProvider: mockQuoteProvider,
+		imageProvider: mockImageProvider,
+		degraded:      true,
+	}
+
+	_, _, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+	var quoteErr *QuoteFetchError
+	var imageErr *ImageFetchError
+	assert.True(t, errors.As(err, &quoteErr), "Expected QuoteFetchError")
+	assert.True(t, errors.As(err, &imageErr), "Expected ImageFetchError")
+}
+
+func TestGetRandomQuoteWithImage_cancelsSiblingOnError(t *testing.T) {
+
+	mockQuoteProvider := new(MockQuoteProvider)
+	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("", fmt.Errorf("fetch quote failed"))
+
+	apiFacade := &APIFacade{
+		quoteProvider: mockQuoteProvider,
+		imageProvider: blockingImageProvider{},
+	}
+
+	_, _, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+	var quoteErr *QuoteFetchError
+	var imageErr *ImageFetchError
+	assert.True(t, errors.As(err, &quoteErr), "Expected QuoteFetchError")
+	assert.False(t, errors.As(err, &imageErr), "Cancelled sibling should not be reported")
+}
+
+func TestGetRandomQuoteWithImage_degradedModeReturnsPlaceholderImage(t *testing.T) {
+
+	mockQuoteProvider := new(MockQuoteProvider)
+	mockImageProvider := new(MockImageProvider)
+
+	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("Random Quote", nil)
+	mockImageProvider.On("GetRandomImage", mock.Anything).Return(image.NewRGBA(image.Rect(0, 0, 0, 0)), fmt.Errorf("fetch image failed"))
+
+	apiFacade := NewAPIFacade(WithDegradedMode()).(*APIFacade)
+	apiFacade.quoteProvider = mockQuoteProvider
+	apiFacade.imageProvider = mockImageProvider
+
+	quote, img, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder().WithWidth(30).WithHeight(20))
+	assert.NoError(t, err)
+	assert.Equal(t, "Random Quote", quote)
+	assert.Equal(t, image.Rect(0, 0, 30, 20), img.Bounds(), "Placeholder should have the requested size")
+}
+
+func TestGetRandomQuoteWithImage_degradedModeReturnsFallbackQuote(t *testing.T) {
+
+	mockQuoteProvider := new(MockQuoteProvider)
+	mockImageProvider := new(MockImageProvider)
+	mockImage := image.NewRGBA(image.Rect(0, 0, 100, 100))
+
+	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("", fmt.Errorf("fetch quote failed"))
+	mockImageProvider.On("GetRandomImage", mock.Anything).Return(mockImage, nil)
+
+	apiFacade := NewAPIFacade(WithDegradedMode(), WithFallbackQuote(quoteapi.Quote{Text: "Fallback"})).(*APIFacade)
+	apiFacade.quoteProvider = mockQuoteProvider
+	apiFacade.imageProvider = mockImageProvider
+
+	quote, img, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, "Fallback", quote)
+	assert.Equal(t, mockImage, img)
+}
+
+func TestNewAPIFacade_withProviders(t *testing.T) {
+
+	mockQuoteProvider := new(MockQuoteProvider)
+	mockImageProvider := new(MockImageProvider)
+	mockImage := image.NewRGBA(image.Rect(0, 0, 100, 100))
+
+	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("Injected Quote", nil)
+	mockImageProvider.On("GetRandomImage", mock.Anything).Return(mockImage, nil)
+
+	apiFacade := NewAPIFacade(WithQuoteProvider(mockQuoteProvider), WithImageProvider(mockImageProvider))
+
+	quote, image, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, "Injected Quote", quote)
+	assert.Equal(t, mockImage, image)
+}
+
+// newRequest builds a pair request with the default quote configuration and the given image configuration.
+func newRequest(t *testing.T, imgCnfgBldr *imageapi.ImageConfigBuilder) api.PairRequest {
+	req, err := api.NewPairRequest(quoteapi.NewQuoteConfigBuilder().Build(), imgCnfgBldr.Build())
+	assert.NoError(t, err)
+	return req
+}
+
+// getRandomQuoteWithImage fetches a pair for the image configuration and returns its quote and image.
+func getRandomQuoteWithImage(t *testing.T, apiFacade api.API, imgCnfgBldr *imageapi.ImageConfigBuilder) (string, image.Image, error) {
+	result, err := apiFacade.GetRandomQuoteWithImage(context.Background(), newRequest(t, imgCnfgBldr))
+	return result.Quote, result.Image, err
+}
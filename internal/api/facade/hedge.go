@@ -0,0 +1,106 @@
+package facade
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// HedgeCounts reports how often requests to one provider were hedged.
+type HedgeCounts struct {
+	// Hedged is the number of requests that outlived the soft deadline and got a second request.
+	Hedged int64 `json:"hedged"`
+	// Won is the number of hedged requests answered by the second request.
+	Won int64 `json:"won"`
+}
+
+// HedgeStats reports the hedge counts of both providers, to help tune the soft deadlines.
+type HedgeStats struct {
+	Quote HedgeCounts `json:"quote"`
+	Image HedgeCounts `json:"image"`
+}
+
+// hedgeCounters records hedge counts of one provider concurrently.
+type hedgeCounters struct {
+	hedged atomic.Int64
+	won    atomic.Int64
+}
+
+func (c *hedgeCounters) snapshot() HedgeCounts {
+	return HedgeCounts{Hedged: c.hedged.Load(), Won: c.won.Load()}
+}
+
+// WithQuoteHedgeDelay sets the soft deadline after which a second, hedged request is sent
+// to the quote provider. The first successful response wins and the other request is
+// cancelled. Zero disables hedging, which is the default.
+func WithQuoteHedgeDelay(delay time.Duration) Option {
+	return func(facade *APIFacade) {
+		facade.quoteHedgeDelay = delay
+	}
+}
+
+// WithImageHedgeDelay sets the soft deadline after which a second, hedged request is sent
+// to the image provider. The first successful response wins and the other request is
+// cancelled. Zero disables hedging, which is the default.
+func WithImageHedgeDelay(delay time.Duration) Option {
+	return func(facade *APIFacade) {
+		facade.imageHedgeDelay = delay
+	}
+}
+
+// HedgeStats returns how many quote and image requests were hedged and how many of them
+// were answered by the hedged request.
+func (facade *APIFacade) HedgeStats() HedgeStats {
+	return HedgeStats{Quote: facade.quoteHedges.snapshot(), Image: facade.imageHedges.snapshot()}
+}
+
+// hedge calls fetch and, when it has not returned after delay, calls it a second time.
+// It returns the first successful result, cancelling the other call, or the last error
+// when both fail.
+func hedge[T any](ctx context.Context, logger *slog.Logger, name string, delay time.Duration, counters *hedgeCounters, fetch func(context.Context) (T, error)) (T, error) {
+	if delay <= 0 {
+		return fetch(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		value  T
+		err    error
+		hedged bool
+	}
+	results := make(chan result, 2)
+	call := func(hedged bool) {
+		value, err := fetch(ctx)
+		results <- result{value: value, err: err, hedged: hedged}
+	}
+
+	go call(false)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	pending := 1
+	select {
+	case r := <-results:
+		return r.value, r.err
+	case <-timer.C:
+		counters.hedged.Add(1)
+		logger.InfoContext(ctx, name+" request exceeded hedge delay, sending hedged request", "delay", delay)
+		go call(true)
+		pending++
+	}
+
+	var r result
+	for ; pending > 0; pending-- {
+		r = <-results
+		if r.err == nil {
+			break
+		}
+	}
+	if r.err == nil && r.hedged {
+		counters.won.Add(1)
+	}
+	return r.value, r.err
+}
@@ -0,0 +1,101 @@
+package facade
+
+import (
+	"context"
+	"errors"
+	"image"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowFirstImageProvider blocks its first call until cancelled and answers later calls immediately.
+type slowFirstImageProvider struct {
+	mu        sync.Mutex
+	calls     int
+	cancelled chan struct{}
+}
+
+func (p *slowFirstImageProvider) GetRandomImage(ctx context.Context, imgCnfg *imageapi.ImageConfigBuilder) (image.Image, error) {
+	p.mu.Lock()
+	p.calls++
+	first := p.calls == 1
+	p.mu.Unlock()
+
+	if first {
+		<-ctx.Done()
+		close(p.cancelled)
+		return nil, ctx.Err()
+	}
+	return image.NewRGBA(image.Rect(0, 0, 1, 1)), nil
+}
+
+func TestHedge_secondRequestWins(t *testing.T) {
+	images := &slowFirstImageProvider{cancelled: make(chan struct{})}
+	apiFacade := NewAPIFacade(
+		WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"one"}}),
+		WithImageProvider(images),
+		WithImageHedgeDelay(10*time.Millisecond),
+	).(*APIFacade)
+
+	quote, img, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, "one", quote)
+	assert.NotNil(t, img)
+	assert.Equal(t, HedgeStats{Image: HedgeCounts{Hedged: 1, Won: 1}}, apiFacade.HedgeStats())
+
+	select {
+	case <-images.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the slow request to be cancelled")
+	}
+}
+
+func TestHedge_fastRequestIsNotHedged(t *testing.T) {
+	quotes := &sequenceQuoteProvider{quotes: []string{"one"}}
+	apiFacade := NewAPIFacade(
+		WithQuoteProvider(quotes),
+		WithImageProvider(&noiseImageProvider{}),
+		WithQuoteHedgeDelay(time.Second),
+		WithImageHedgeDelay(time.Second),
+	).(*APIFacade)
+
+	_, _, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, quotes.calls)
+	assert.Equal(t, HedgeStats{}, apiFacade.HedgeStats())
+}
+
+func TestHedge_waitsForSuccessAfterFailure(t *testing.T) {
+	var counters hedgeCounters
+	calls := 0
+	var mu sync.Mutex
+	value, err := hedge(context.Background(), logging.Discard(), "Test", 10*time.Millisecond, &counters, func(ctx context.Context) (string, error) {
+		mu.Lock()
+		calls++
+		call := calls
+		mu.Unlock()
+		if call == 1 {
+			time.Sleep(20 * time.Millisecond)
+			return "slow", nil
+		}
+		return "", errors.New("hedged request failed")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "slow", value)
+	assert.Equal(t, HedgeCounts{Hedged: 1}, counters.snapshot())
+}
+
+func TestHedge_returnsLastErrorWhenBothFail(t *testing.T) {
+	var counters hedgeCounters
+	_, err := hedge(context.Background(), logging.Discard(), "Test", time.Millisecond, &counters, func(ctx context.Context) (string, error) {
+		time.Sleep(5 * time.Millisecond)
+		return "", errors.New("request failed")
+	})
+	assert.EqualError(t, err, "request failed")
+	assert.Equal(t, HedgeCounts{Hedged: 1}, counters.snapshot())
+}
@@ -0,0 +1,123 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"slices"
+	"sync"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/keywords"
+	"github.com/ramyad/tucows/internal/mood"
+)
+
+const (
+	DefaultMoodCandidates = 4
+	// SomberScore and BleakScore are the sentiment scores at or below which mood
+	// filters add grayscale, and grayscale and blur, to the image.
+	SomberScore = -0.2
+	BleakScore  = -0.5
+)
+
+// WithMoodFilters makes the facade score each quote with the bundled sentiment lexicon
+// and fetch the image with filters matching its mood: grayscale for somber quotes and
+// grayscale and blur for bleak ones. The image is only requested once the quote is known.
+func WithMoodFilters() Option {
+	return func(facade *APIFacade) {
+		facade.moodFilters = true
+	}
+}
+
+// WithMoodPalette makes the facade fetch a batch of candidate images alongside each quote
+// and pick the one whose brightness and saturation best match the quote's sentiment.
+// When the image configuration has a seed, candidate i uses the seed suffixed with -i,
+// so the pairing is deterministic under a seed. Pinned images are fetched as is.
+func WithMoodPalette(candidates int) Option {
+	return func(facade *APIFacade) {
+		facade.moodCandidates = max(candidates, 2)
+	}
+}
+
+// getImage fetches the image of a pair, applying mood pairing when enabled. awaitQuote
+// blocks until the quote of the pair is fetched and returns it, or a zero quote on failure.
+func (facade *APIFacade) getImage(ctx context.Context, imgCnfgBldr *imageapi.ImageConfigBuilder, awaitQuote func() quoteapi.Quote) (image.Image, error) {
+	config := imgCnfgBldr.Build()
+
+	if facade.moodFilters {
+		score := mood.Score(awaitQuote().Text)
+		imgCnfgBldr = cloneImageConfig(imgCnfgBldr).WithFilters(moodFilters(score, config.Filters))
+	}
+	if facade.searchKeywords > 0 && config.ImageID == "" {
+		if _, ok := facade.imageProvider.(imageapi.ImageSearcher); ok {
+			imgCnfgBldr = cloneImageConfig(imgCnfgBldr).WithKeywords(keywords.Extract(awaitQuote().Text, facade.searchKeywords))
+		}
+	}
+
+	if facade.moodCandidates < 2 || config.ImageID != "" {
+		return facade.getSingleImage(ctx, imgCnfgBldr)
+	}
+
+	candidates := make([]image.Image, facade.moodCandidates)
+	errs := make([]error, facade.moodCandidates)
+	var wg sync.WaitGroup
+	for i := range candidates {
+		candidateConfig := cloneImageConfig(imgCnfgBldr)
+		if config.Seed != "" {
+			candidateConfig.WithSeed(fmt.Sprintf("%s-%d", config.Seed, i))
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			candidates[i], errs[i] = facade.getSingleImage(ctx, candidateConfig)
+		}(i)
+	}
+	wg.Wait()
+
+	fetched := make([]image.Image, 0, len(candidates))
+	for i, candidate := range candidates {
+		if errs[i] == nil {
+			fetched = append(fetched, candidate)
+		}
+	}
+	if len(fetched) == 0 {
+		return nil, errs[0]
+	}
+	return fetched[mood.BestMatch(mood.Score(awaitQuote().Text), fetched)], nil
+}
+
+// getSingleImage fetches one image, hedging slow requests when configured. Configurations
+// with keywords are searched for when the provider supports it.
+func (facade *APIFacade) getSingleImage(ctx context.Context, imgCnfgBldr *imageapi.ImageConfigBuilder) (image.Image, error) {
+	return hedge(ctx, facade.logger, "Image", facade.imageHedgeDelay, &facade.imageHedges, func(ctx context.Context) (image.Image, error) {
+		return facade.searchOrRandomImage(ctx, imgCnfgBldr)
+	})
+}
+
+// moodFilters returns the filters to add for a quote with the given sentiment score,
+// leaving out those already requested.
+func moodFilters(score float64, requested imageapi.ImageFilters) imageapi.ImageFilters {
+	var filters imageapi.ImageFilters
+	if score <= SomberScore {
+		filters = append(filters, imageapi.ImageFilterGrayscale)
+	}
+	if score <= BleakScore {
+		filters = append(filters, imageapi.ImageFilterBlur)
+	}
+	return slices.DeleteFunc(filters, func(filter string) bool {
+		return slices.Contains(requested, filter)
+	})
+}
+
+// cloneImageConfig copies a configuration so it can be changed without affecting the caller's builder.
+func cloneImageConfig(imgCnfgBldr *imageapi.ImageConfigBuilder) *imageapi.ImageConfigBuilder {
+	config := imgCnfgBldr.Build()
+	return imageapi.NewImageConfigBuilder().
+		WithWidth(config.Width).
+		WithHeight(config.Height).
+		WithFilters(slices.Clone(config.Filters)).
+		WithSeed(config.Seed).
+		WithImageID(config.ImageID).
+		WithKeywords(slices.Clone(config.Keywords))
+}
@@ -0,0 +1,171 @@
+package facade
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// paletteImageProvider returns a solid image whose colour is picked by the seed suffix
+// and records the configurations it was called with.
+type paletteImageProvider struct {
+	mu       sync.Mutex
+	configs  []imageapi.ImageFilters
+	seeds    []string
+	keywords [][]string
+}
+
+var paletteColors = []color.RGBA{
+	{R: 128, G: 128, B: 128, A: 255},
+	{R: 255, G: 220, B: 40, A: 255},
+	{R: 20, G: 20, B: 30, A: 255},
+}
+
+func (p *paletteImageProvider) GetRandomImage(ctx context.Context, imgCnfg *imageapi.ImageConfigBuilder) (image.Image, error) {
+	config := imgCnfg.Build()
+	p.mu.Lock()
+	p.configs = append(p.configs, config.Filters)
+	p.seeds = append(p.seeds, config.Seed)
+	p.keywords = append(p.keywords, config.Keywords)
+	p.mu.Unlock()
+
+	c := paletteColors[0]
+	for i, suffix := range []string{"-0", "-1", "-2"} {
+		if strings.HasSuffix(config.Seed, suffix) {
+			c = paletteColors[i]
+		}
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	draw.Draw(img, img.Bounds(), &image.Uniform{c}, image.Point{}, draw.Src)
+	return img, nil
+}
+
+func TestMoodFilters_matchQuoteSentiment(t *testing.T) {
+	tests := []struct {
+		quote    string
+		expected imageapi.ImageFilters
+	}{
+		{"Happiness is a warm smile.", nil},
+		{"Doubt is a hard burden.", imageapi.ImageFilters{imageapi.ImageFilterGrayscale}},
+		{"Grief and despair follow every loss.", imageapi.ImageFilters{imageapi.ImageFilterGrayscale, imageapi.ImageFilterBlur}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.quote, func(t *testing.T) {
+			images := &paletteImageProvider{}
+			apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{tt.quote}}), WithImageProvider(images), WithMoodFilters())
+			imageConfig := imageapi.NewImageConfigBuilder()
+
+			_, _, err := getRandomQuoteWithImage(t, apiFacade, imageConfig)
+			assert.NoError(t, err)
+			assert.Equal(t, []imageapi.ImageFilters{tt.expected}, images.configs)
+			assert.Empty(t, imageConfig.Build().Filters, "Expected the caller's configuration to be left untouched")
+		})
+	}
+}
+
+func TestMoodFilters_keepRequestedFilters(t *testing.T) {
+	images := &paletteImageProvider{}
+	apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"Grief and despair."}}), WithImageProvider(images), WithMoodFilters())
+
+	_, _, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder().WithFilters(imageapi.ImageFilters{imageapi.ImageFilterBlur}))
+	assert.NoError(t, err)
+	assert.Equal(t, []imageapi.ImageFilters{{imageapi.ImageFilterBlur, imageapi.ImageFilterGrayscale}}, images.configs)
+}
+
+func TestMoodPalette_picksMatchingCandidate(t *testing.T) {
+	tests := []struct {
+		quote    string
+		expected color.RGBA
+	}{
+		{"Happiness is a wonderful gift.", paletteColors[1]},
+		{"Grief and despair follow every loss.", paletteColors[2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.quote, func(t *testing.T) {
+			images := &paletteImageProvider{}
+			apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{tt.quote}}), WithImageProvider(images), WithMoodPalette(3))
+
+			_, img, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder().WithSeed("kiosk"))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, img.At(0, 0))
+
+			sort.Strings(images.seeds)
+			assert.Equal(t, []string{"kiosk-0", "kiosk-1", "kiosk-2"}, images.seeds)
+		})
+	}
+}
+
+// searchingPaletteImageProvider is a paletteImageProvider that also searches, recording the keywords.
+type searchingPaletteImageProvider struct {
+	*paletteImageProvider
+}
+
+func (p searchingPaletteImageProvider) SearchImage(ctx context.Context, imgCnfg *imageapi.ImageConfigBuilder) (image.Image, error) {
+	return p.GetRandomImage(ctx, imgCnfg)
+}
+
+// newPicsumServer serves solid JPEG images like picsum, colored by the suffix of the seed in the path.
+func newPicsumServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := paletteColors[0]
+		for i, suffix := range []string{"-0/", "-1/", "-2/"} {
+			if strings.Contains(r.URL.Path, suffix) {
+				c = paletteColors[i]
+			}
+		}
+		img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+		draw.Draw(img, img.Bounds(), &image.Uniform{c}, image.Point{}, draw.Src)
+		assert.NoError(t, jpeg.Encode(w, img, nil))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMoodPalette_seedIsDeterministicThroughPicsum(t *testing.T) {
+	server := newPicsumServer(t)
+	images := imageapi.NewImageAPIBuilder().WithBaseURL(server.URL).Build()
+	apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"Happiness is a wonderful gift."}}), WithImageProvider(images), WithMoodPalette(3))
+
+	for i := 0; i < 3; i++ {
+		_, img, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder().WithSeed("kiosk"))
+		assert.NoError(t, err)
+		metadata, ok := imageapi.MetadataOf(img)
+		assert.True(t, ok, "Expected image metadata")
+		assert.Contains(t, metadata.Source, "/seed/kiosk-1/", "Expected the same seed to pick the same candidate")
+	}
+}
+
+func TestMoodPalette_keepsKeywords(t *testing.T) {
+	images := searchingPaletteImageProvider{&paletteImageProvider{}}
+	quotes := &sequenceQuoteProvider{quotes: []string{"The ocean waves never stop."}}
+	apiFacade := NewAPIFacade(WithQuoteProvider(quotes), WithImageProvider(images), WithMoodPalette(3), WithQuoteKeywords(DefaultSearchKeywords))
+
+	_, _, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+	assert.NoError(t, err)
+	assert.Len(t, images.keywords, 3)
+	for _, keywords := range images.keywords {
+		assert.Contains(t, keywords, "ocean", "Expected every palette candidate to keep the search keywords")
+	}
+}
+
+func TestMoodPalette_pinnedImageIsFetchedOnce(t *testing.T) {
+	images := &paletteImageProvider{}
+	apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"one"}}), WithImageProvider(images), WithMoodPalette(3))
+
+	_, _, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder().WithImageID("237"))
+	assert.NoError(t, err)
+	assert.Len(t, images.configs, 1)
+}
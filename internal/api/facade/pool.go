@@ -0,0 +1,195 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/logging"
+)
+
+const (
+	// MaxPooledConfigs bounds how many distinct configurations get a prefetch pool.
+	// Requests for further configurations are always fetched live.
+	MaxPooledConfigs = 16
+	// PoolRefillTimeout bounds each background fetch that refills a pool.
+	PoolRefillTimeout = 30 * time.Second
+)
+
+// WithPrefetchPool keeps up to size ready quote/image pairs for each requested configuration,
+// refilled in the background, so single pairs are served without waiting for the providers.
+// Concurrent identical requests that miss the pool share a single live fetch.
+// Batches and subscriptions always fetch live, since they need distinct pairs.
+func WithPrefetchPool(size int) Option {
+	return func(facade *APIFacade) {
+		if size > 0 {
+			facade.pool = newPrefetchPool(size)
+		}
+	}
+}
+
+// pooledPair is a quote and image kept ready in a prefetch pool.
+type pooledPair struct {
+	quote quoteapi.Quote
+	image image.Image
+}
+
+// prefetchPool keeps ready pairs per configuration key.
+type prefetchPool struct {
+	size    int
+	flights flightGroup
+
+	mu      sync.Mutex
+	entries map[string]*poolEntry
+}
+
+// poolEntry holds the ready pairs of one configuration.
+type poolEntry struct {
+	pairs     []pooledPair
+	refilling bool
+}
+
+func newPrefetchPool(size int) *prefetchPool {
+	return &prefetchPool{size: size, entries: map[string]*poolEntry{}}
+}
+
+// nextPair returns a pair for the configuration, taken from the prefetch pool when one is
+// ready and fetched live otherwise. Without a pool every pair is fetched live.
+func (facade *APIFacade) nextPair(ctx context.Context, qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) (quoteapi.Quote, image.Image, error) {
+	if facade.pool == nil {
+		return facade.fetchQuoteAndImage(ctx, qtcnfbldr, imgCnfgBldr)
+	}
+
+	key := poolKey(qtcnfbldr, imgCnfgBldr)
+	pooled, ok := facade.pool.take(key)
+	defer facade.refill(key, qtcnfbldr, imgCnfgBldr)
+	if ok {
+		return pooled.quote, pooled.image, nil
+	}
+
+	// The shared fetch outlives the caller that started it, so that cancelling one request
+	// does not fail the others waiting for it.
+	fetchCtx := context.WithoutCancel(ctx)
+	pair, err := facade.pool.flights.Do(ctx, key, func() (pooledPair, error) {
+		ctx, cancel := context.WithTimeout(fetchCtx, PoolRefillTimeout)
+		defer cancel()
+		quote, img, err := facade.fetchQuoteAndImage(ctx, qtcnfbldr, imgCnfgBldr)
+		return pooledPair{quote: quote, image: img}, err
+	})
+	return pair.quote, pair.image, err
+}
+
+// take removes and returns a ready pair for the configuration key, if any.
+func (pool *prefetchPool) take(key string) (pooledPair, bool) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	entry, ok := pool.entries[key]
+	if !ok || len(entry.pairs) == 0 {
+		return pooledPair{}, false
+	}
+	pair := entry.pairs[0]
+	entry.pairs = entry.pairs[1:]
+	return pair, true
+}
+
+// refill starts filling the pool of the configuration key in the background, unless it is
+// already being refilled or the pool has no room for another configuration.
+func (facade *APIFacade) refill(key string, qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) {
+	pool := facade.pool
+	pool.mu.Lock()
+	entry, ok := pool.entries[key]
+	if !ok {
+		if len(pool.entries) >= MaxPooledConfigs {
+			pool.mu.Unlock()
+			return
+		}
+		entry = &poolEntry{}
+		pool.entries[key] = entry
+	}
+	if entry.refilling || len(entry.pairs) >= pool.size {
+		pool.mu.Unlock()
+		return
+	}
+	entry.refilling = true
+	pool.mu.Unlock()
+
+	go func() {
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), PoolRefillTimeout)
+			quote, img, err := facade.fetchQuoteAndImage(ctx, qtcnfbldr, imgCnfgBldr)
+			cancel()
+
+			pool.mu.Lock()
+			if err == nil {
+				entry.pairs = append(entry.pairs, pooledPair{quote: quote, image: img})
+			}
+			if err != nil || len(entry.pairs) >= pool.size {
+				entry.refilling = false
+				pool.mu.Unlock()
+				if err != nil {
+					facade.logger.Warn("Failed to refill prefetch pool, serving live until the next request", logging.Err(err))
+				}
+				return
+			}
+			pool.mu.Unlock()
+		}
+	}()
+}
+
+// poolKey identifies the configuration of a request in the prefetch pool.
+func poolKey(qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) string {
+	quoteConfig := qtcnfbldr.Build()
+	imageConfig := imgCnfgBldr.Build()
+	return fmt.Sprintf("%d|%dx%d|%s|%s|%s", quoteConfig.Key, imageConfig.Width, imageConfig.Height,
+		strings.Join(imageConfig.Filters, ","), imageConfig.Seed, imageConfig.ImageID)
+}
+
+// flightGroup collapses concurrent calls with the same key into a single call whose
+// result is shared by all callers.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a call in progress or completed within a flightGroup.
+type flight struct {
+	done chan struct{}
+	pair pooledPair
+	err  error
+}
+
+// Do starts fn in the background unless a call with the same key is already in flight,
+// and waits for the result of the call until ctx is done. The call keeps running for the
+// other callers when ctx is done.
+func (g *flightGroup) Do(ctx context.Context, key string, fn func() (pooledPair, error)) (pooledPair, error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+	f, ok := g.flights[key]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		g.flights[key] = f
+		go func() {
+			f.pair, f.err = fn()
+			g.mu.Lock()
+			delete(g.flights, key)
+			g.mu.Unlock()
+			close(f.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.pair, f.err
+	case <-ctx.Done():
+		return pooledPair{}, ctx.Err()
+	}
+}
@@ -0,0 +1,137 @@
+package facade
+
+import (
+	"context"
+	"image"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// gatedImageProvider blocks every call until the gate is closed and counts calls.
+type gatedImageProvider struct {
+	gate  chan struct{}
+	calls atomic.Int32
+}
+
+func (p *gatedImageProvider) GetRandomImage(ctx context.Context, imgCnfg *imageapi.ImageConfigBuilder) (image.Image, error) {
+	p.calls.Add(1)
+	select {
+	case <-p.gate:
+		return image.NewRGBA(image.Rect(0, 0, 1, 1)), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (facade *APIFacade) pooledCount(qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) int {
+	facade.pool.mu.Lock()
+	defer facade.pool.mu.Unlock()
+	entry, ok := facade.pool.entries[poolKey(qtcnfbldr, imgCnfgBldr)]
+	if !ok {
+		return 0
+	}
+	return len(entry.pairs)
+}
+
+func TestPrefetchPool_servesFromPoolAfterWarmUp(t *testing.T) {
+	quotes := &sequenceQuoteProvider{quotes: []string{"one", "two", "three", "four"}}
+	apiFacade := NewAPIFacade(WithQuoteProvider(quotes), WithImageProvider(&noiseImageProvider{}), WithPrefetchPool(2)).(*APIFacade)
+	quoteConfig, imageConfig := quoteapi.NewQuoteConfigBuilder(), imageapi.NewImageConfigBuilder().WithWidth(10).WithHeight(10)
+
+	quote, _, err := getRandomQuoteWithImage(t, apiFacade, imageConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "one", quote, "Expected the first request to be fetched live")
+	assert.Eventually(t, func() bool { return apiFacade.pooledCount(quoteConfig, imageConfig) == 2 }, time.Second, time.Millisecond)
+
+	quote, _, err = getRandomQuoteWithImage(t, apiFacade, imageConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "two", quote, "Expected the second request to be served from the pool")
+	assert.Eventually(t, func() bool { return apiFacade.pooledCount(quoteConfig, imageConfig) == 2 }, time.Second, time.Millisecond)
+
+	quotes.mu.Lock()
+	defer quotes.mu.Unlock()
+	assert.Equal(t, 4, quotes.calls, "Expected the pool to be refilled up to its size")
+}
+
+func TestPrefetchPool_separatesConfigurations(t *testing.T) {
+	apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"one"}}), WithImageProvider(&noiseImageProvider{}), WithPrefetchPool(1)).(*APIFacade)
+	quoteConfig := quoteapi.NewQuoteConfigBuilder()
+	small := imageapi.NewImageConfigBuilder().WithWidth(10).WithHeight(10)
+	large := imageapi.NewImageConfigBuilder().WithWidth(20).WithHeight(20)
+
+	_, _, err := getRandomQuoteWithImage(t, apiFacade, small)
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool { return apiFacade.pooledCount(quoteConfig, small) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 0, apiFacade.pooledCount(quoteConfig, large))
+}
+
+func TestPrefetchPool_collapsesConcurrentMisses(t *testing.T) {
+	images := &gatedImageProvider{gate: make(chan struct{})}
+	apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"one"}}), WithImageProvider(images), WithPrefetchPool(1)).(*APIFacade)
+	imageConfig := imageapi.NewImageConfigBuilder()
+
+	var wg sync.WaitGroup
+	results := make([]image.Image, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, results[i], _ = getRandomQuoteWithImage(t, apiFacade, imageConfig)
+		}(i)
+	}
+	assert.Eventually(t, func() bool { return images.calls.Load() >= 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(1), images.calls.Load(), "Expected concurrent identical requests to share one fetch")
+
+	close(images.gate)
+	wg.Wait()
+	assert.Same(t, results[0], results[1])
+	assert.Same(t, results[0], results[2])
+}
+
+func TestPrefetchPool_cancelledCallerDoesNotFailOthers(t *testing.T) {
+	images := &gatedImageProvider{gate: make(chan struct{})}
+	apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"one"}}), WithImageProvider(images), WithPrefetchPool(1)).(*APIFacade)
+	imageConfig := imageapi.NewImageConfigBuilder()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := apiFacade.GetRandomQuoteWithImage(ctx, newRequest(t, imageConfig))
+		firstErr <- err
+	}()
+	assert.Eventually(t, func() bool { return images.calls.Load() >= 1 }, time.Second, time.Millisecond)
+
+	secondErr := make(chan error, 1)
+	var second image.Image
+	go func() {
+		var err error
+		_, second, err = getRandomQuoteWithImage(t, apiFacade, imageConfig)
+		secondErr <- err
+	}()
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+
+	close(images.gate)
+	assert.NoError(t, <-secondErr, "Expected the shared fetch to survive the first caller's cancellation")
+	assert.NotNil(t, second)
+}
+
+func TestFlightGroup_sequentialCallsAreNotShared(t *testing.T) {
+	var group flightGroup
+	calls := 0
+	for i := 0; i < 2; i++ {
+		_, err := group.Do(context.Background(), "key", func() (pooledPair, error) {
+			calls++
+			return pooledPair{}, nil
+		})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
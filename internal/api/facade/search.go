@@ -0,0 +1,38 @@
+package facade
+
+import (
+	"context"
+	"errors"
+	"image"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+)
+
+const DefaultSearchKeywords = 3
+
+// WithQuoteKeywords makes the facade extract up to n keywords from each quote and search
+// for a matching image when the image provider implements imageapi.ImageSearcher.
+// The image is only requested once the quote is known. Providers without search, pinned
+// images and searches without a match fall back to a random image.
+func WithQuoteKeywords(n int) Option {
+	return func(facade *APIFacade) {
+		facade.searchKeywords = max(n, 0)
+	}
+}
+
+// searchOrRandomImage searches for an image matching the keywords of the configuration,
+// falling back to a random image when there are none, the provider cannot search or
+// nothing matches.
+func (facade *APIFacade) searchOrRandomImage(ctx context.Context, imgCnfgBldr *imageapi.ImageConfigBuilder) (image.Image, error) {
+	searcher, ok := facade.imageProvider.(imageapi.ImageSearcher)
+	if !ok || len(imgCnfgBldr.Build().Keywords) == 0 {
+		return facade.imageProvider.GetRandomImage(ctx, imgCnfgBldr)
+	}
+
+	img, err := searcher.SearchImage(ctx, imgCnfgBldr)
+	if errors.Is(err, imageapi.ErrNoSearchMatch) {
+		facade.logger.InfoContext(ctx, "No image matches keywords, using a random image", "keywords", imgCnfgBldr.Build().Keywords)
+		return facade.imageProvider.GetRandomImage(ctx, imgCnfgBldr)
+	}
+	return img, err
+}
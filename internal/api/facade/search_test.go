@@ -0,0 +1,64 @@
+package facade
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSearchDir creates a directory image provider whose file names act as a local search index.
+func newSearchDir(t *testing.T, names ...string) imageapi.ImageProvider {
+	dir := t.TempDir()
+	for _, name := range names {
+		file, err := os.Create(filepath.Join(dir, name+".png"))
+		assert.NoError(t, err)
+		assert.NoError(t, png.Encode(file, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+		assert.NoError(t, file.Close())
+	}
+	provider, err := imageapi.NewDirImageProvider(dir)
+	assert.NoError(t, err)
+	return provider
+}
+
+func imageID(t *testing.T, img image.Image) string {
+	metadata, ok := imageapi.MetadataOf(img)
+	assert.True(t, ok, "Expected image metadata")
+	return metadata.ID
+}
+
+func TestQuoteKeywords_searchesMatchingImage(t *testing.T) {
+	images := newSearchDir(t, "city-night", "ocean-waves", "mountain-snow")
+	quotes := &sequenceQuoteProvider{quotes: []string{"The ocean waves never stop."}}
+	apiFacade := NewAPIFacade(WithQuoteProvider(quotes), WithImageProvider(images), WithQuoteKeywords(DefaultSearchKeywords))
+
+	for i := 0; i < 5; i++ {
+		_, img, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+		assert.NoError(t, err)
+		assert.Equal(t, "ocean-waves", imageID(t, img))
+	}
+}
+
+func TestQuoteKeywords_fallsBackWithoutMatch(t *testing.T) {
+	images := newSearchDir(t, "city-night")
+	quotes := &sequenceQuoteProvider{quotes: []string{"The ocean waves never stop."}}
+	apiFacade := NewAPIFacade(WithQuoteProvider(quotes), WithImageProvider(images), WithQuoteKeywords(DefaultSearchKeywords))
+
+	_, img, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, "city-night", imageID(t, img))
+}
+
+func TestQuoteKeywords_fallsBackWithoutSearch(t *testing.T) {
+	images := &paletteImageProvider{}
+	apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"The ocean waves."}}), WithImageProvider(images), WithQuoteKeywords(DefaultSearchKeywords))
+
+	_, img, err := getRandomQuoteWithImage(t, apiFacade, imageapi.NewImageConfigBuilder())
+	assert.NoError(t, err)
+	assert.NotNil(t, img)
+	assert.Len(t, images.configs, 1)
+}
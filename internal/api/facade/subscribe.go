@@ -0,0 +1,92 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/logging"
+)
+
+// DefaultSubscribeInterval is used by Subscribe when no positive interval is given.
+const DefaultSubscribeInterval = 30 * time.Second
+
+// Subscribe emits a fresh quote/image pair every interval until ctx is cancelled, after which
+// the returned channel is closed. The first pair is emitted as soon as it is fetched, and each
+// following pair is prefetched right after the previous one is delivered so it is ready when due.
+// When a fetch fails the last good pair is emitted again; a pair with Err set is only emitted
+// when no pair has been fetched successfully yet. An invalid request emits a single pair with Err set.
+func (facade *APIFacade) Subscribe(ctx context.Context, interval time.Duration, req api.PairRequest) <-chan api.Pair {
+	if interval <= 0 {
+		interval = DefaultSubscribeInterval
+	}
+
+	pairs := make(chan api.Pair)
+	go func() {
+		defer close(pairs)
+
+		if err := req.Validate(); err != nil {
+			select {
+			case pairs <- api.Pair{Err: err}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		qtcnfbldr, imgCnfgBldr := req.QuoteConfigBuilder(), req.ImageConfigBuilder()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last api.Pair
+		next := facade.prefetch(ctx, qtcnfbldr, imgCnfgBldr)
+		for {
+			var pair api.Pair
+			select {
+			case pair = <-next:
+			case <-ctx.Done():
+				return
+			}
+
+			switch {
+			case pair.Err != nil && ctx.Err() != nil:
+				return
+			case pair.Err != nil && last.Image != nil:
+				facade.logger.WarnContext(ctx, "Failed to fetch next pair, keeping the last one", logging.Err(pair.Err))
+				pair = last
+			case pair.Err == nil:
+				last = pair
+			}
+			next = facade.prefetch(ctx, qtcnfbldr, imgCnfgBldr)
+
+			select {
+			case pairs <- pair:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return pairs
+}
+
+// prefetch fetches a pair in the background and delivers it on the returned channel,
+// which is buffered so the fetch never blocks on an abandoned subscription.
+func (facade *APIFacade) prefetch(ctx context.Context, qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) <-chan api.Pair {
+	next := make(chan api.Pair, 1)
+	go func() {
+		quote, image, err := facade.fetchQuoteAndImage(ctx, qtcnfbldr, imgCnfgBldr)
+		if err != nil {
+			next <- api.Pair{Err: err}
+			return
+		}
+		next <- api.Pair{PairResult: api.PairResult{Quote: quote.Text, Author: quote.Author, Image: image}}
+	}()
+	return next
+}
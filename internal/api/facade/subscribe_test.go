@@ -0,0 +1,74 @@
+package facade
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSubscribe_emitsPairsOnInterval(t *testing.T) {
+	quotes := &sequenceQuoteProvider{quotes: []string{"one", "two", "three"}}
+	apiFacade := NewAPIFacade(WithQuoteProvider(quotes), WithImageProvider(&noiseImageProvider{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pairs := apiFacade.Subscribe(ctx, 10*time.Millisecond, newRequest(t, imageapi.NewImageConfigBuilder()))
+
+	for _, expected := range []string{"one", "two", "three"} {
+		pair := <-pairs
+		assert.NoError(t, pair.Err)
+		assert.Equal(t, expected, pair.Quote)
+		assert.NotNil(t, pair.Image)
+	}
+}
+
+func TestSubscribe_keepsLastGoodPair(t *testing.T) {
+	quotes := &sequenceQuoteProvider{quotes: []string{"good"}, failFromIndex: 1}
+	apiFacade := NewAPIFacade(WithQuoteProvider(quotes), WithImageProvider(&noiseImageProvider{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pairs := apiFacade.Subscribe(ctx, 10*time.Millisecond, newRequest(t, imageapi.NewImageConfigBuilder()))
+
+	first := <-pairs
+	for i := 0; i < 2; i++ {
+		pair := <-pairs
+		assert.NoError(t, pair.Err)
+		assert.Equal(t, first, pair)
+	}
+}
+
+func TestSubscribe_reportsErrorWithoutGoodPair(t *testing.T) {
+	mockQuoteProvider := new(MockQuoteProvider)
+	mockQuoteProvider.On("GetRandomQuote", mock.Anything).Return("", errors.New("quote failed"))
+	apiFacade := NewAPIFacade(WithQuoteProvider(mockQuoteProvider), WithImageProvider(&noiseImageProvider{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pairs := apiFacade.Subscribe(ctx, 10*time.Millisecond, newRequest(t, imageapi.NewImageConfigBuilder()))
+
+	pair := <-pairs
+	var quoteErr *QuoteFetchError
+	assert.ErrorAs(t, pair.Err, &quoteErr)
+}
+
+func TestSubscribe_closesOnCancel(t *testing.T) {
+	apiFacade := NewAPIFacade(WithQuoteProvider(&sequenceQuoteProvider{quotes: []string{"one"}}), WithImageProvider(&noiseImageProvider{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pairs := apiFacade.Subscribe(ctx, time.Hour, newRequest(t, imageapi.NewImageConfigBuilder()))
+	<-pairs
+	cancel()
+
+	select {
+	case _, ok := <-pairs:
+		assert.False(t, ok, "Expected the channel to be closed")
+	case <-time.After(time.Second):
+		t.Fatal("Expected the channel to be closed after cancellation")
+	}
+}
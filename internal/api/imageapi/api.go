@@ -2,15 +2,21 @@
 package imageapi
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"image"
-	"image/jpeg"
-	"log"
+	_ "image/jpeg"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	retry "github.com/avast/retry-go"
+	"github.com/ramyad/tucows/internal/logging"
 	"github.com/ramyad/tucows/internal/shared"
 )
 
@@ -24,6 +30,14 @@ const (
 	defaultBaseUrl     = "https://picsum.photos"
 	RetryAttempts      = 3
 	RetryDelay         = time.Second
+	// DefaultMaxResponseBytes caps how many bytes of an image response are read.
+	DefaultMaxResponseBytes = 10 << 20
+	// DefaultMaxImagePixels caps the decoded pixel count (width*height) of an image.
+	DefaultMaxImagePixels = MaxImageWidth * MaxImageHeight
+	// picsumIDHeader carries the catalog ID of the image returned by picsum.
+	picsumIDHeader = "Picsum-ID"
+	// drainLimit bounds how much of a rejected body is discarded to allow connection reuse.
+	drainLimit = 64 << 10
 )
 
 const (
@@ -34,7 +48,7 @@ const (
 
 // ImageProvider is an interface that defines the contract for fetching random images.
 type ImageProvider interface {
-	GetRandomImage(imgCnfg *ImageConfigBuilder) (image.Image, error)
+	GetRandomImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error)
 }
 
 // ImageAPIBuilder provides methods for building an imageAPI instance.
@@ -42,21 +56,29 @@ type ImageAPIBuilder struct {
 	api *imageAPI
 }
 
-// imageAPI represents an image API with a base URL.
+// imageAPI represents an image API with a base URL, response limits and retry attempts.
+// Zero limits fall back to DefaultMaxResponseBytes, DefaultMaxImagePixels and RetryAttempts.
 type imageAPI struct {
-	baseURL string
+	baseURL          string
+	maxResponseBytes int64
+	maxPixels        int
+	retryAttempts    int
+	logger           *slog.Logger
 }
 
-// ImageConfigBuilder provides methods for building an imageConfig instance.
+// ImageConfigBuilder provides methods for building an ImageConfig value.
 type ImageConfigBuilder struct {
-	config imageConfig
+	config ImageConfig
 }
 
-// imageConfig represents configuration options for fetching images.
-type imageConfig struct {
-	Filters ImageFilters
-	Width   int
-	Height  int
+// ImageConfig represents configuration options for fetching images.
+type ImageConfig struct {
+	Filters  ImageFilters
+	Width    int
+	Height   int
+	Seed     string
+	ImageID  string
+	Keywords []string
 }
 
 // NewImageAPIBuilder creates a new ImageAPIBuilder instance with the default base URL.
@@ -74,6 +96,30 @@ func (iab *ImageAPIBuilder) WithBaseURL(baseURL string) *ImageAPIBuilder {
 	return iab
 }
 
+// WithMaxResponseBytes sets the maximum number of bytes read from an image response and returns the builder instance.
+func (iab *ImageAPIBuilder) WithMaxResponseBytes(n int64) *ImageAPIBuilder {
+	iab.api.maxResponseBytes = n
+	return iab
+}
+
+// WithMaxPixels sets the maximum pixel count (width*height) accepted before decoding and returns the builder instance.
+func (iab *ImageAPIBuilder) WithMaxPixels(n int) *ImageAPIBuilder {
+	iab.api.maxPixels = n
+	return iab
+}
+
+// WithRetryAttempts sets how many times a request is attempted before giving up and returns the builder instance.
+func (iab *ImageAPIBuilder) WithRetryAttempts(n int) *ImageAPIBuilder {
+	iab.api.retryAttempts = n
+	return iab
+}
+
+// WithLogger sets the logger for request failures and returns the builder instance.
+func (iab *ImageAPIBuilder) WithLogger(logger *slog.Logger) *ImageAPIBuilder {
+	iab.api.logger = logger
+	return iab
+}
+
 // Build constructs and returns an ImageProvider interface.
 func (iab *ImageAPIBuilder) Build() ImageProvider {
 	return iab.api
@@ -82,7 +128,7 @@ func (iab *ImageAPIBuilder) Build() ImageProvider {
 // NewImageConfigBuilder creates a new ImageConfigBuilder instance with default dimensions.
 func NewImageConfigBuilder() *ImageConfigBuilder {
 	return &ImageConfigBuilder{
-		config: imageConfig{
+		config: ImageConfig{
 			Width:  DefaultImageWidth,
 			Height: DefaultImageHeight,
 		},
@@ -92,7 +138,7 @@ func NewImageConfigBuilder() *ImageConfigBuilder {
 // WithWidth sets the image width in the configuration and returns the builder instance.
 func (icb *ImageConfigBuilder) WithWidth(w int) *ImageConfigBuilder {
 	if w > MaxImageWidth {
-		log.Printf("[%s] Requested image width exceeds maximum allowed width.", shared.LogLevelWarning)
+		slog.Warn("Requested image width exceeds maximum allowed width", "width", w, "max", MaxImageWidth)
 		w = MaxImageWidth
 	}
 	icb.config.Width = w
@@ -102,7 +148,7 @@ func (icb *ImageConfigBuilder) WithWidth(w int) *ImageConfigBuilder {
 // WithHeight sets the image height in the configuration and returns the builder instance.
 func (icb *ImageConfigBuilder) WithHeight(h int) *ImageConfigBuilder {
 	if h > MaxImageHeight {
-		log.Printf("[%s] Requested image height exceeds maximum allowed height.", shared.LogLevelWarning)
+		slog.Warn("Requested image height exceeds maximum allowed height", "height", h, "max", MaxImageHeight)
 		h = MaxImageHeight
 	}
 	icb.config.Height = h
@@ -111,61 +157,198 @@ func (icb *ImageConfigBuilder) WithHeight(h int) *ImageConfigBuilder {
 
 // WithFilters adds image filters to the configuration and returns the builder instance.
 func (icb *ImageConfigBuilder) WithFilters(filters ImageFilters) *ImageConfigBuilder {
-	icb.config.Filters = append(icb.config.Filters, filters...)
+	// Clip so the append never writes into an array shared with a previously built ImageConfig.
+	icb.config.Filters = append(slices.Clip(icb.config.Filters), filters...)
+	return icb
+}
+
+// WithSeed sets the seed used by providers that support deterministic images and returns the builder instance.
+func (icb *ImageConfigBuilder) WithSeed(seed string) *ImageConfigBuilder {
+	icb.config.Seed = seed
 	return icb
 }
 
-// Build constructs and returns an imageConfig instance.
-func (icb *ImageConfigBuilder) Build() imageConfig {
-	return icb.config
+// WithImageID pins a specific catalog image instead of a random one and returns the builder instance.
+func (icb *ImageConfigBuilder) WithImageID(id string) *ImageConfigBuilder {
+	icb.config.ImageID = id
+	return icb
+}
+
+// WithKeywords sets the search hint used by providers implementing ImageSearcher and returns the builder instance.
+func (icb *ImageConfigBuilder) WithKeywords(keywords []string) *ImageConfigBuilder {
+	icb.config.Keywords = keywords
+	return icb
+}
+
+// Build constructs and returns an ImageConfig value that does not share memory with the builder.
+func (icb *ImageConfigBuilder) Build() ImageConfig {
+	config := icb.config
+	config.Filters = slices.Clone(config.Filters)
+	config.Keywords = slices.Clone(config.Keywords)
+	return config
 }
 
 // GetRandomImage fetches a random image using the provided configuration from the image API.
-func (api *imageAPI) GetRandomImage(imgCnfg *ImageConfigBuilder) (image.Image, error) {
+func (api *imageAPI) GetRandomImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error) {
 	path := api.buildPath(imgCnfg.Build())
-	var resp *http.Response
+	return fetchImage(ctx, fetchRequest{
+		path:      path,
+		maxBytes:  api.responseLimit(),
+		maxPixels: api.pixelLimit(),
+		attempts:  api.attemptLimit(),
+		logger:    logging.OrDefault(api.logger).With(logging.ProviderKey, "picsum"),
+		formats:   picsumFormats,
+	})
+}
+
+// fetchRequest describes an image download and the limits it must respect.
+type fetchRequest struct {
+	path      string
+	header    http.Header
+	maxBytes  int64
+	maxPixels int
+	attempts  int
+	logger    *slog.Logger
+	// formats lists the accepted image formats, as named by image.RegisterFormat.
+	formats []string
+}
+
+var (
+	// picsumFormats are the formats served by Lorem Picsum.
+	picsumFormats = []string{"jpeg"}
+	// templateFormats are the formats accepted from URL-template services.
+	templateFormats = []string{"jpeg", "png", "gif"}
+)
+
+// fetchImage requests the path with the given headers, retrying transient failures up to the
+// given number of attempts until ctx is cancelled, and decodes the response within the size limits.
+func fetchImage(ctx context.Context, fetch fetchRequest) (image.Image, error) {
 	var image image.Image
+	logger := fetch.logger
 
+	attempt := 0
 	err := retry.Do(
 		func() error {
-			var err error
-			resp, err = http.Get(path)
+			attempt++
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fetch.path, nil)
 			if err != nil {
-				log.Printf("[%s] Get request Error: %v", shared.LogLevelError, err)
-				return err
+				return retry.Unrecoverable(err)
+			}
+			for key, values := range fetch.header {
+				req.Header[key] = values
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				logger.ErrorContext(ctx, "Image request failed", logging.AttemptKey, attempt, logging.Err(err))
+				return shared.RequestError(err)
 			}
+			defer drainAndClose(resp.Body)
+
 			if resp.StatusCode != http.StatusOK {
-				log.Printf("[%s] Received non-200 status code: %d", shared.LogLevelError, resp.StatusCode)
-				return fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+				logger.ErrorContext(ctx, "Received non-200 status code", logging.AttemptKey, attempt, "status", resp.StatusCode)
+				return shared.StatusError(resp.StatusCode)
 			}
-			image, err = jpeg.Decode(resp.Body)
+			decoded, err := decodeImage(resp, fetch.maxBytes, fetch.maxPixels, fetch.formats)
 			if err != nil {
-				log.Printf("[%s] JSON Parse Error: %v", shared.LogLevelError, err)
+				logger.ErrorContext(ctx, "Failed to decode image", logging.AttemptKey, attempt, logging.Err(err))
+				if isRejection(err) {
+					return retry.Unrecoverable(err)
+				}
 				return err
 			}
+			image = withMetadata(decoded, ImageMetadata{
+				ID:     resp.Header.Get(picsumIDHeader),
+				Source: resp.Request.URL.String(),
+			})
 
 			return nil
 		},
-		retry.Attempts(RetryAttempts),
+		retry.Context(ctx),
+		retry.Attempts(uint(fetch.attempts)),
 		retry.DelayType(retry.BackOffDelay),
 		retry.Delay(RetryDelay),
 		retry.OnRetry(func(n uint, err error) {
-			if n == uint(RetryAttempts-1) {
-				log.Printf("[%s] Warning: Reached max retry attempts - 1.", shared.LogLevelWarning)
+			if n == uint(fetch.attempts-1) {
+				logger.WarnContext(ctx, "Retrying image request for the last time", logging.AttemptKey, n+1)
 			}
 		}),
 	)
 
 	if err != nil {
-		log.Printf("[%s] Failed to get image from random image API after retries: %v", shared.LogLevelError, err)
-		return nil, fmt.Errorf("failed to get image from random image API after retries: %s", err)
+		logger.ErrorContext(ctx, "Failed to get image after retries", logging.Err(err))
+		return nil, fmt.Errorf("failed to get image from random image API after retries: %w", shared.FlattenRetryError(err))
 	}
 
 	return image, nil
 }
 
+// responseLimit returns the configured response byte limit or the default.
+func (api *imageAPI) responseLimit() int64 {
+	if api.maxResponseBytes > 0 {
+		return api.maxResponseBytes
+	}
+	return DefaultMaxResponseBytes
+}
+
+// attemptLimit returns the configured number of retry attempts or the default.
+func (api *imageAPI) attemptLimit() int {
+	if api.retryAttempts > 0 {
+		return api.retryAttempts
+	}
+	return RetryAttempts
+}
+
+// pixelLimit returns the configured pixel limit or the default.
+func (api *imageAPI) pixelLimit() int {
+	if api.maxPixels > 0 {
+		return api.maxPixels
+	}
+	return DefaultMaxImagePixels
+}
+
+// decodeImage reads at most maxBytes from the response, checks the image format and
+// dimensions with image.DecodeConfig and only then decodes the full image.
+func decodeImage(resp *http.Response, maxBytes int64, maxPixels int, formats []string) (image.Image, error) {
+	if resp.ContentLength > maxBytes {
+		return nil, &ResponseTooLargeError{Limit: maxBytes, Size: resp.ContentLength}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, &ResponseTooLargeError{Limit: maxBytes, Size: -1}
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+	if !slices.Contains(formats, format) {
+		return nil, &UnsupportedFormatError{Format: format}
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, &ImageTooLargeError{Width: config.Width, Height: config.Height, MaxPixels: maxPixels}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+	return img, nil
+}
+
+// drainAndClose discards a bounded amount of the remaining body so the
+// underlying connection can be reused, then closes it.
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, drainLimit))
+	body.Close()
+}
+
 // buildPath constructs the URL path for fetching an image based on the provided configuration.
-func (api *imageAPI) buildPath(imgCnfg imageConfig) string {
+func (api *imageAPI) buildPath(imgCnfg ImageConfig) string {
 	sizeOptions := fmt.Sprintf("%d/%d", imgCnfg.Width, imgCnfg.Height)
 	var filterBuilder strings.Builder
 	for i, filter := range imgCnfg.Filters {
@@ -182,6 +365,13 @@ func (api *imageAPI) buildPath(imgCnfg imageConfig) string {
 	filterOptions := filterBuilder.String()
 	var pathBuilder strings.Builder
 	pathBuilder.WriteString(api.baseURL)
+	if imgCnfg.ImageID != "" {
+		pathBuilder.WriteString("/id/")
+		pathBuilder.WriteString(url.PathEscape(imgCnfg.ImageID))
+	} else if imgCnfg.Seed != "" {
+		pathBuilder.WriteString("/seed/")
+		pathBuilder.WriteString(url.PathEscape(imgCnfg.Seed))
+	}
 	pathBuilder.WriteString("/")
 	pathBuilder.WriteString(sizeOptions)
 	pathBuilder.WriteString(".jpg")
@@ -1,42 +1,64 @@
 package imageapi
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/ramyad/tucows/internal/shared"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetRandomImage_success(t *testing.T) {
 	api := NewImageAPIBuilder().Build()
-	imageConfig := NewImageConfigBuilder().WithWidth(800).WithHeight(1200).WithFilters(ImageFilters{"grayscale"})
+	ImageConfig := NewImageConfigBuilder().WithWidth(800).WithHeight(1200).WithFilters(ImageFilters{"grayscale"})
 
-	_, err := api.GetRandomImage(imageConfig)
+	_, err := api.GetRandomImage(context.Background(), ImageConfig)
 	assert.NoError(t, err, "Expected no error for this image config")
 }
 
 func TestGetRandomImage_Error(t *testing.T) {
 	api := NewImageAPIBuilder().WithBaseURL("http://unavailable.unavailable").Build()
-	imageConfig := NewImageConfigBuilder()
+	ImageConfig := NewImageConfigBuilder()
 	expectedError := fmt.Errorf("failed to get image from random image API after retries")
-	_, err := api.GetRandomImage(imageConfig)
+	_, err := api.GetRandomImage(context.Background(), ImageConfig)
 	assert.Error(t, err, "Expected error due to unavailable API")
 	assert.Contains(t, err.Error(), expectedError.Error(), "Expected error message mismatch")
 }
 
 func TestBuildPathWithImageSizeAndFilters(t *testing.T) {
 	api := NewImageAPIBuilder().Build()
-	imageConfig := NewImageConfigBuilder().WithWidth(400).WithHeight(600).WithFilters(ImageFilters{"blur", "grayscale"}).Build()
+	ImageConfig := NewImageConfigBuilder().WithWidth(400).WithHeight(600).WithFilters(ImageFilters{"blur", "grayscale"}).Build()
 	expectedPath := "https://picsum.photos/400/600.jpg?blur&grayscale"
-	resultPath := api.(*imageAPI).buildPath(imageConfig)
+	resultPath := api.(*imageAPI).buildPath(ImageConfig)
 	assert.Equal(t, expectedPath, resultPath, "Path built with incorrect format")
 }
 
+func TestBuildPathWithSeed(t *testing.T) {
+	api := NewImageAPIBuilder().Build()
+	ImageConfig := NewImageConfigBuilder().WithWidth(400).WithHeight(600).WithSeed("kiosk 1").Build()
+	expectedPath := "https://picsum.photos/seed/kiosk%201/400/600.jpg"
+	resultPath := api.(*imageAPI).buildPath(ImageConfig)
+	assert.Equal(t, expectedPath, resultPath, "Path built with incorrect format")
+
+	ImageConfig = NewImageConfigBuilder().WithSeed("kiosk").WithImageID("237").Build()
+	expectedPath = "https://picsum.photos/id/237/200/300.jpg"
+	resultPath = api.(*imageAPI).buildPath(ImageConfig)
+	assert.Equal(t, expectedPath, resultPath, "Expected a pinned image to ignore the seed")
+}
+
 func TestBuildPathWithoutImageConfig(t *testing.T) {
 	api := NewImageAPIBuilder().Build()
-	imageConfig := NewImageConfigBuilder().Build()
+	ImageConfig := NewImageConfigBuilder().Build()
 	expectedPath := "https://picsum.photos/200/300.jpg"
-	resultPath := api.(*imageAPI).buildPath(imageConfig)
+	resultPath := api.(*imageAPI).buildPath(ImageConfig)
 	assert.Equal(t, expectedPath, resultPath, "Path built with incorrect format")
 }
 
@@ -49,7 +71,7 @@ func TestImageAPIBuilder(t *testing.T) {
 }
 
 func TestImageConfigBuilder(t *testing.T) {
-	expected := imageConfig{
+	expected := ImageConfig{
 		Width:   100,
 		Height:  200,
 		Filters: ImageFilters{"grayscale"},
@@ -75,3 +97,104 @@ func TestImageConfigBuilder_HeightReplacesHigherValueWithMax(t *testing.T) {
 
 	assert.Equal(t, MaxImageHeight, config.Height, "Height should be replaced with MaxImageHeight")
 }
+
+func newJPEGServer(t *testing.T, width, height int) *httptest.Server {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil)
+	assert.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buf.Bytes())
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetRandomImage_LocalServer(t *testing.T) {
+	server := newJPEGServer(t, 20, 10)
+	api := NewImageAPIBuilder().WithBaseURL(server.URL).Build()
+
+	img, err := api.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 20, 10), img.Bounds())
+}
+
+func TestGetRandomImage_ResponseTooLarge(t *testing.T) {
+	server := newJPEGServer(t, 20, 10)
+	api := NewImageAPIBuilder().WithBaseURL(server.URL).WithMaxResponseBytes(16).Build()
+
+	_, err := api.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	var tooLarge *ResponseTooLargeError
+	assert.True(t, errors.As(err, &tooLarge), "Expected ResponseTooLargeError, got %v", err)
+	assert.Equal(t, int64(16), tooLarge.Limit)
+}
+
+func TestGetRandomImage_ImageTooLarge(t *testing.T) {
+	server := newJPEGServer(t, 20, 10)
+	api := NewImageAPIBuilder().WithBaseURL(server.URL).WithMaxPixels(100).Build()
+
+	_, err := api.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	var tooLarge *ImageTooLargeError
+	assert.True(t, errors.As(err, &tooLarge), "Expected ImageTooLargeError, got %v", err)
+	assert.Equal(t, 20, tooLarge.Width)
+	assert.Equal(t, 10, tooLarge.Height)
+}
+
+func TestGetRandomImage_DecodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not an image"))
+	}))
+	defer server.Close()
+	api := NewImageAPIBuilder().WithBaseURL(server.URL).Build()
+
+	_, err := api.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	var decodeErr *DecodeError
+	assert.True(t, errors.As(err, &decodeErr), "Expected DecodeError, got %v", err)
+}
+
+func TestGetRandomImage_RejectsPNG(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+	api := NewImageAPIBuilder().WithBaseURL(server.URL).WithRetryAttempts(1).Build()
+
+	_, err := api.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	var formatErr *UnsupportedFormatError
+	assert.True(t, errors.As(err, &formatErr), "Expected UnsupportedFormatError, got %v", err)
+}
+
+func TestGetRandomImage_ErrorKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{"server error", http.StatusBadGateway, "", shared.ErrUpstreamUnavailable},
+		{"rate limited", http.StatusTooManyRequests, "", shared.ErrRateLimited},
+		{"not found", http.StatusNotFound, "", shared.ErrUpstreamBadResponse},
+		{"not an image", http.StatusOK, "not an image", shared.ErrDecode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			api := NewImageAPIBuilder().WithBaseURL(server.URL).WithRetryAttempts(1).Build()
+
+			_, err := api.GetRandomImage(context.Background(), NewImageConfigBuilder())
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Equal(t, tt.expected, shared.Kind(err))
+		})
+	}
+
+	server := newJPEGServer(t, 20, 10)
+	api := NewImageAPIBuilder().WithBaseURL(server.URL).WithMaxPixels(100).Build()
+	_, err := api.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	assert.ErrorIs(t, err, shared.ErrUpstreamBadResponse)
+}
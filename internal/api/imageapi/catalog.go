@@ -0,0 +1,154 @@
+package imageapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	retry "github.com/avast/retry-go"
+	"github.com/ramyad/tucows/internal/logging"
+	"github.com/ramyad/tucows/internal/shared"
+)
+
+const (
+	DefaultCatalogPage  = 1
+	DefaultCatalogLimit = 30
+	MaxCatalogLimit     = 100
+	ThumbnailWidth      = 200
+	ThumbnailHeight     = 150
+)
+
+// ImageInfo describes a single image listed in the image catalog.
+type ImageInfo struct {
+	ID           string `json:"id"`
+	Author       string `json:"author"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	URL          string `json:"url"`
+	DownloadURL  string `json:"download_url"`
+	ThumbnailURL string `json:"-"`
+}
+
+// CatalogQuery represents paging and filtering options for listing catalog images.
+// Author filtering is a case-insensitive substring match applied to the requested page.
+type CatalogQuery struct {
+	Page   int
+	Limit  int
+	Author string
+}
+
+// ImageCatalog is an interface that defines the contract for browsing the images of an image API.
+type ImageCatalog interface {
+	ListImages(ctx context.Context, query CatalogQuery) ([]ImageInfo, error)
+	GetImageInfo(ctx context.Context, id string) (ImageInfo, error)
+}
+
+// BuildCatalog constructs and returns an ImageCatalog interface backed by the same image API.
+func (iab *ImageAPIBuilder) BuildCatalog() ImageCatalog {
+	return iab.api
+}
+
+// ListImages returns one page of catalog images, optionally filtered by author.
+// Retries stop as soon as ctx is cancelled.
+func (api *imageAPI) ListImages(ctx context.Context, query CatalogQuery) ([]ImageInfo, error) {
+	query = normalizeCatalogQuery(query)
+	path := fmt.Sprintf("%s/v2/list?page=%d&limit=%d", api.baseURL, query.Page, query.Limit)
+
+	var images []ImageInfo
+	if err := api.getJSON(ctx, path, &images); err != nil {
+		return nil, fmt.Errorf("failed to list images from image catalog: %w", err)
+	}
+
+	filtered := images[:0]
+	for _, info := range images {
+		if query.Author != "" && !strings.Contains(strings.ToLower(info.Author), strings.ToLower(query.Author)) {
+			continue
+		}
+		info.ThumbnailURL = api.thumbnailURL(info.ID)
+		filtered = append(filtered, info)
+	}
+	return filtered, nil
+}
+
+// GetImageInfo looks up a single catalog image by its ID.
+// Retries stop as soon as ctx is cancelled.
+func (api *imageAPI) GetImageInfo(ctx context.Context, id string) (ImageInfo, error) {
+	var info ImageInfo
+	path := fmt.Sprintf("%s/id/%s/info", api.baseURL, url.PathEscape(id))
+	if err := api.getJSON(ctx, path, &info); err != nil {
+		return ImageInfo{}, fmt.Errorf("failed to get image %s from image catalog: %w", id, err)
+	}
+	info.ThumbnailURL = api.thumbnailURL(info.ID)
+	return info, nil
+}
+
+// thumbnailURL returns the URL of a small preview of the catalog image with the given ID.
+func (api *imageAPI) thumbnailURL(id string) string {
+	return fmt.Sprintf("%s/id/%s/%d/%d.jpg", api.baseURL, url.PathEscape(id), ThumbnailWidth, ThumbnailHeight)
+}
+
+// getJSON fetches path and decodes the JSON response into v, retrying transient failures
+// until ctx is cancelled.
+func (api *imageAPI) getJSON(ctx context.Context, path string, v interface{}) error {
+	logger := logging.OrDefault(api.logger).With(logging.ProviderKey, "picsum")
+	attempt := 0
+	err := retry.Do(
+		func() error {
+			attempt++
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+			if err != nil {
+				return retry.Unrecoverable(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				logger.ErrorContext(ctx, "Catalog request failed", logging.AttemptKey, attempt, logging.Err(err))
+				return shared.RequestError(err)
+			}
+			defer drainAndClose(resp.Body)
+
+			if resp.StatusCode == http.StatusNotFound {
+				return retry.Unrecoverable(shared.InvalidInput("received non-200 status code: %d", resp.StatusCode))
+			}
+			if resp.StatusCode != http.StatusOK {
+				logger.ErrorContext(ctx, "Received non-200 status code", logging.AttemptKey, attempt, "status", resp.StatusCode)
+				return shared.StatusError(resp.StatusCode)
+			}
+
+			err = json.NewDecoder(io.LimitReader(resp.Body, api.responseLimit())).Decode(v)
+			if err != nil {
+				logger.ErrorContext(ctx, "Failed to parse catalog response", logging.AttemptKey, attempt, logging.Err(err))
+				return shared.Wrap(shared.ErrDecode, fmt.Errorf("failed to parse catalog response: %w", err))
+			}
+			return nil
+		},
+		retry.Context(ctx),
+		retry.Attempts(uint(api.attemptLimit())),
+		retry.DelayType(retry.BackOffDelay),
+		retry.Delay(RetryDelay),
+	)
+	if err != nil {
+		return shared.FlattenRetryError(err)
+	}
+	return nil
+}
+
+// normalizeCatalogQuery applies defaults and bounds to the paging options.
+func normalizeCatalogQuery(query CatalogQuery) CatalogQuery {
+	if query.Page < 1 {
+		query.Page = DefaultCatalogPage
+	}
+	if query.Limit < 1 {
+		query.Limit = DefaultCatalogLimit
+	}
+	query.Limit = min(query.Limit, MaxCatalogLimit)
+	return query
+}
+
+// String formats the image info as a single catalog listing line.
+func (info ImageInfo) String() string {
+	return fmt.Sprintf("%-6s %-30s %5dx%-5d %s", info.ID, info.Author, info.Width, info.Height, info.URL)
+}
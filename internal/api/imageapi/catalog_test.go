@@ -0,0 +1,83 @@
+package imageapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCatalogServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/list", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("page"))
+		assert.Equal(t, "3", r.URL.Query().Get("limit"))
+		w.Write([]byte(`[
+			{"id":"0","author":"Alejandro Escamilla","width":5616,"height":3744},
+			{"id":"1","author":"Paul Jarvis","width":5000,"height":3333},
+			{"id":"2","author":"alejandro escamilla","width":4000,"height":3000}
+		]`))
+	})
+	mux.HandleFunc("/id/10/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"10","author":"Paul Jarvis","width":2500,"height":1667}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListImages_FiltersByAuthor(t *testing.T) {
+	server := newCatalogServer(t)
+	catalog := NewImageAPIBuilder().WithBaseURL(server.URL).BuildCatalog()
+
+	images, err := catalog.ListImages(context.Background(), CatalogQuery{Page: 2, Limit: 3, Author: "Escamilla"})
+	assert.NoError(t, err)
+	assert.Len(t, images, 2)
+	assert.Equal(t, "0", images[0].ID)
+	assert.Equal(t, "2", images[1].ID)
+	assert.Equal(t, server.URL+"/id/0/200/150.jpg", images[0].ThumbnailURL)
+}
+
+func TestGetImageInfo(t *testing.T) {
+	server := newCatalogServer(t)
+	catalog := NewImageAPIBuilder().WithBaseURL(server.URL).BuildCatalog()
+
+	info, err := catalog.GetImageInfo(context.Background(), "10")
+	assert.NoError(t, err)
+	assert.Equal(t, "Paul Jarvis", info.Author)
+	assert.Equal(t, 2500, info.Width)
+}
+
+func TestGetImageInfo_NotFound(t *testing.T) {
+	server := newCatalogServer(t)
+	catalog := NewImageAPIBuilder().WithBaseURL(server.URL).BuildCatalog()
+
+	_, err := catalog.GetImageInfo(context.Background(), "404")
+	assert.Error(t, err, "Expected error for unknown image id")
+}
+
+func TestListImages_stopsRetryingWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	catalog := NewImageAPIBuilder().WithBaseURL(server.URL).BuildCatalog()
+
+	_, err := catalog.ListImages(ctx, CatalogQuery{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, requests, "A cancelled request must not be retried")
+}
+
+func TestBuildPathWithImageID(t *testing.T) {
+	api := NewImageAPIBuilder().Build()
+	ImageConfig := NewImageConfigBuilder().WithWidth(400).WithHeight(600).WithImageID("237").Build()
+	expectedPath := "https://picsum.photos/id/237/400/600.jpg"
+	resultPath := api.(*imageAPI).buildPath(ImageConfig)
+	assert.Equal(t, expectedPath, resultPath, "Path built with incorrect format")
+}
@@ -0,0 +1,129 @@
+package imageapi
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log/slog"
+	"sync"
+
+	"github.com/ramyad/tucows/internal/logging"
+)
+
+const (
+	DefaultDedupHistorySize  = 20
+	DefaultDedupThreshold    = 6
+	DefaultDedupMaxRefetches = 3
+)
+
+// DedupImageProviderBuilder provides methods for building a dedupImageProvider instance.
+type DedupImageProviderBuilder struct {
+	provider *dedupImageProvider
+}
+
+// dedupImageProvider wraps an ImageProvider and re-fetches images whose perceptual
+// hash is within threshold bits of one of the recently returned images.
+type dedupImageProvider struct {
+	inner        ImageProvider
+	historySize  int
+	threshold    int
+	maxRefetches int
+	logger       *slog.Logger
+
+	mu      sync.Mutex
+	history []uint64
+}
+
+// NewDedupImageProviderBuilder creates a new DedupImageProviderBuilder wrapping the given provider with default settings.
+func NewDedupImageProviderBuilder(inner ImageProvider) *DedupImageProviderBuilder {
+	return &DedupImageProviderBuilder{
+		provider: &dedupImageProvider{
+			inner:        inner,
+			historySize:  DefaultDedupHistorySize,
+			threshold:    DefaultDedupThreshold,
+			maxRefetches: DefaultDedupMaxRefetches,
+		},
+	}
+}
+
+// WithHistorySize sets how many recent image hashes are remembered and returns the builder instance.
+func (dpb *DedupImageProviderBuilder) WithHistorySize(n int) *DedupImageProviderBuilder {
+	dpb.provider.historySize = n
+	return dpb
+}
+
+// WithThreshold sets the maximum Hamming distance at which two images are considered duplicates and returns the builder instance.
+func (dpb *DedupImageProviderBuilder) WithThreshold(bits int) *DedupImageProviderBuilder {
+	dpb.provider.threshold = bits
+	return dpb
+}
+
+// WithMaxRefetches sets how many extra fetches may be spent replacing a duplicate and returns the builder instance.
+func (dpb *DedupImageProviderBuilder) WithMaxRefetches(n int) *DedupImageProviderBuilder {
+	dpb.provider.maxRefetches = n
+	return dpb
+}
+
+// WithLogger sets the logger for refetched duplicates and returns the builder instance.
+func (dpb *DedupImageProviderBuilder) WithLogger(logger *slog.Logger) *DedupImageProviderBuilder {
+	dpb.provider.logger = logger
+	return dpb
+}
+
+// Build constructs and returns an ImageProvider interface.
+func (dpb *DedupImageProviderBuilder) Build() ImageProvider {
+	return dpb.provider
+}
+
+// GetRandomImage fetches an image from the wrapped provider, re-fetching near-duplicates
+// of recent images within the refetch budget. The returned image carries its dHash
+// in its metadata. Pinned images are never re-fetched.
+func (p *dedupImageProvider) GetRandomImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error) {
+	pinned := imgCnfg.Build().ImageID != ""
+	logger := logging.OrDefault(p.logger)
+
+	for attempt := 0; ; attempt++ {
+		img, err := p.inner.GetRandomImage(ctx, imgCnfg)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata, _ := MetadataOf(img)
+		metadata.Hash = DifferenceHash(img)
+
+		duplicate := p.remember(metadata.Hash, pinned || attempt >= p.maxRefetches)
+		if pinned || !duplicate {
+			return withMetadata(img, metadata), nil
+		}
+
+		if attempt >= p.maxRefetches {
+			logger.WarnContext(ctx, "Returning duplicate image after refetches", "refetches", attempt)
+			return withMetadata(img, metadata), nil
+		}
+		logger.InfoContext(ctx, "Fetched near-duplicate image, refetching", "hash", fmt.Sprintf("%016x", metadata.Hash))
+	}
+}
+
+// remember reports whether hash is within the threshold of any remembered hash, and records
+// it in the history window unless it is such a duplicate and force is false. The oldest entry
+// is evicted when the window is full. Checking and recording under one lock keeps concurrent
+// fetches from both accepting the same image.
+func (p *dedupImageProvider) remember(hash uint64, force bool) (duplicate bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, seen := range p.history {
+		if HammingDistance(hash, seen) <= p.threshold {
+			duplicate = true
+			break
+		}
+	}
+	if duplicate && !force || p.historySize <= 0 {
+		return duplicate
+	}
+	p.history = append(p.history, hash)
+	if len(p.history) > p.historySize {
+		p.history = p.history[len(p.history)-p.historySize:]
+	}
+	return duplicate
+}
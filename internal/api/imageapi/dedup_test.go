@@ -0,0 +1,127 @@
+package imageapi
+
+import (
+	"context"
+	"errors"
+	"image"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sequenceImageProvider returns the given images in order, one per call.
+type sequenceImageProvider struct {
+	images []image.Image
+	calls  int
+}
+
+func (p *sequenceImageProvider) GetRandomImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error) {
+	if p.calls >= len(p.images) {
+		return nil, errors.New("no more images")
+	}
+	img := p.images[p.calls]
+	p.calls++
+	return img, nil
+}
+
+func TestDedupImageProvider_RefetchesDuplicate(t *testing.T) {
+	first := gradientImage(64, 48, false, 0)
+	second := gradientImage(64, 48, true, 0)
+	inner := &sequenceImageProvider{images: []image.Image{first, first, second}}
+	provider := NewDedupImageProviderBuilder(inner).Build()
+
+	img, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	assert.NoError(t, err)
+	metadata, ok := MetadataOf(img)
+	assert.True(t, ok, "Expected metadata on deduplicated image")
+	assert.Equal(t, DifferenceHash(first), metadata.Hash)
+
+	img, err = provider.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inner.calls, "Expected the duplicate to be refetched")
+	metadata, _ = MetadataOf(img)
+	assert.Equal(t, DifferenceHash(second), metadata.Hash)
+}
+
+func TestDedupImageProvider_BudgetExhausted(t *testing.T) {
+	first := gradientImage(64, 48, false, 0)
+	inner := &sequenceImageProvider{images: []image.Image{first, first, first}}
+	provider := NewDedupImageProviderBuilder(inner).WithMaxRefetches(1).Build()
+
+	_, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	assert.NoError(t, err)
+	img, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	assert.NoError(t, err, "Expected the duplicate to be returned once the budget is spent")
+	assert.NotNil(t, img)
+	assert.Equal(t, 3, inner.calls)
+}
+
+func TestDedupImageProvider_HistoryWindow(t *testing.T) {
+	first := gradientImage(64, 48, false, 0)
+	second := gradientImage(64, 48, true, 0)
+	inner := &sequenceImageProvider{images: []image.Image{first, second, first}}
+	provider := NewDedupImageProviderBuilder(inner).WithHistorySize(1).WithMaxRefetches(0).Build()
+
+	for i := 0; i < 3; i++ {
+		_, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []uint64{DifferenceHash(first)}, provider.(*dedupImageProvider).history)
+}
+
+func TestDedupImageProvider_PinnedImageNotRefetched(t *testing.T) {
+	first := gradientImage(64, 48, false, 0)
+	inner := &sequenceImageProvider{images: []image.Image{first, first}}
+	provider := NewDedupImageProviderBuilder(inner).Build()
+
+	for i := 0; i < 2; i++ {
+		_, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder().WithImageID("10"))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, inner.calls)
+}
+
+// barrierImageProvider returns first to the first two calls once both are made, and second afterwards.
+type barrierImageProvider struct {
+	first, second image.Image
+	arrived       sync.WaitGroup
+	mu            sync.Mutex
+	calls         int
+}
+
+func (p *barrierImageProvider) GetRandomImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error) {
+	p.mu.Lock()
+	p.calls++
+	call := p.calls
+	p.mu.Unlock()
+	if call > 2 {
+		return p.second, nil
+	}
+	p.arrived.Done()
+	p.arrived.Wait()
+	return p.first, nil
+}
+
+func TestDedupImageProvider_ConcurrentFetchesAcceptOneDuplicate(t *testing.T) {
+	inner := &barrierImageProvider{first: gradientImage(64, 48, false, 0), second: gradientImage(64, 48, true, 0)}
+	inner.arrived.Add(2)
+	provider := NewDedupImageProviderBuilder(inner).Build()
+
+	hashes := make([]uint64, 2)
+	var wg sync.WaitGroup
+	for i := range hashes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			img, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder())
+			assert.NoError(t, err)
+			metadata, _ := MetadataOf(img)
+			hashes[i] = metadata.Hash
+		}(i)
+	}
+	wg.Wait()
+
+	assert.NotEqual(t, hashes[0], hashes[1], "Expected only one of the concurrent fetches to accept the image")
+	assert.Equal(t, 3, inner.calls)
+}
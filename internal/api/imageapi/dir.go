@@ -0,0 +1,150 @@
+package imageapi
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ramyad/tucows/internal/shared"
+)
+
+// imageExtensions lists the file extensions served by the directory image provider.
+var imageExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true}
+
+// Ensure that *dirImageProvider can search images by file name.
+var _ ImageSearcher = (*dirImageProvider)(nil)
+
+// dirImageProvider is an ImageProvider that picks images from a local directory.
+type dirImageProvider struct {
+	dir string
+}
+
+// NewDirImageProvider creates an ImageProvider that serves a random JPEG, PNG or GIF image from dir.
+// A configured seed picks the same image every time and an image ID pins the file with that name.
+func NewDirImageProvider(dir string) (ImageProvider, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &dirImageProvider{dir: dir}, nil
+}
+
+// GetRandomImage picks an image from the directory and returns it with the requested size and filters applied.
+func (p *dirImageProvider) GetRandomImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	config := imgCnfg.Build()
+
+	paths, err := p.imagePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := pickImagePath(paths, config)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := decodeImageFile(path)
+	if err != nil {
+		return nil, err
+	}
+	img = applyFilters(fillImage(img, config.Width, config.Height), config.Filters)
+	return withMetadata(img, ImageMetadata{ID: imageName(path), Source: path}), nil
+}
+
+// SearchImage picks an image whose file name contains the most keywords, choosing between
+// equally good matches like GetRandomImage. It returns ErrNoSearchMatch when no file name
+// contains any of the keywords.
+func (p *dirImageProvider) SearchImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	config := imgCnfg.Build()
+
+	paths, err := p.imagePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	best := 0
+	for _, path := range paths {
+		name := strings.ToLower(imageName(path))
+		score := 0
+		for _, keyword := range config.Keywords {
+			if keyword != "" && strings.Contains(name, strings.ToLower(keyword)) {
+				score++
+			}
+		}
+		switch {
+		case score > best:
+			best, matches = score, []string{path}
+		case score == best && score > 0:
+			matches = append(matches, path)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, ErrNoSearchMatch
+	}
+
+	path, err := pickImagePath(matches, ImageConfig{Seed: config.Seed})
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := decodeImageFile(path)
+	if err != nil {
+		return nil, err
+	}
+	img = applyFilters(fillImage(img, config.Width, config.Height), config.Filters)
+	return withMetadata(img, ImageMetadata{ID: imageName(path), Source: path}), nil
+}
+
+// imagePaths lists the image files in the directory in a stable order.
+func (p *dirImageProvider) imagePaths() ([]string, error) {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && imageExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			paths = append(paths, filepath.Join(p.dir, entry.Name()))
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no images found in %s", p.dir)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// pickImagePath selects the pinned, seeded or a random path from paths.
+func pickImagePath(paths []string, config ImageConfig) (string, error) {
+	if config.ImageID != "" {
+		for _, path := range paths {
+			if imageName(path) == config.ImageID {
+				return path, nil
+			}
+		}
+		return "", shared.InvalidInput("image %q not found", config.ImageID)
+	}
+	if config.Seed != "" {
+		hash := fnv.New64a()
+		hash.Write([]byte(config.Seed))
+		return paths[hash.Sum64()%uint64(len(paths))], nil
+	}
+	return paths[rand.Intn(len(paths))], nil
+}
@@ -0,0 +1,84 @@
+package imageapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ramyad/tucows/internal/shared"
+)
+
+// ResponseTooLargeError is returned when an image response exceeds the maximum allowed size.
+// Size is -1 when the response did not declare its length up front.
+type ResponseTooLargeError struct {
+	Limit int64
+	Size  int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	if e.Size < 0 {
+		return fmt.Sprintf("image response exceeds %d bytes", e.Limit)
+	}
+	return fmt.Sprintf("image response of %d bytes exceeds %d bytes", e.Size, e.Limit)
+}
+
+// Is classifies the error as shared.ErrUpstreamBadResponse.
+func (e *ResponseTooLargeError) Is(target error) bool {
+	return target == shared.ErrUpstreamBadResponse
+}
+
+// ImageTooLargeError is returned when the declared image dimensions exceed the maximum pixel count.
+type ImageTooLargeError struct {
+	Width     int
+	Height    int
+	MaxPixels int
+}
+
+func (e *ImageTooLargeError) Error() string {
+	return fmt.Sprintf("image dimensions %dx%d exceed %d pixels", e.Width, e.Height, e.MaxPixels)
+}
+
+// Is classifies the error as shared.ErrUpstreamBadResponse.
+func (e *ImageTooLargeError) Is(target error) bool {
+	return target == shared.ErrUpstreamBadResponse
+}
+
+// UnsupportedFormatError is returned when the response is a valid image in a format the provider does not accept.
+type UnsupportedFormatError struct {
+	Format string
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	return fmt.Sprintf("unsupported image format: %s", e.Format)
+}
+
+// Is classifies the error as shared.ErrDecode.
+func (e *UnsupportedFormatError) Is(target error) bool {
+	return target == shared.ErrDecode
+}
+
+// DecodeError is returned when the response body cannot be decoded as an image.
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode image: %v", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is classifies the error as shared.ErrDecode.
+func (e *DecodeError) Is(target error) bool {
+	return target == shared.ErrDecode
+}
+
+// isRejection reports whether err is a deliberate rejection of the response
+// that retrying would not fix.
+func isRejection(err error) bool {
+	var tooLarge *ResponseTooLargeError
+	var tooManyPixels *ImageTooLargeError
+	var unsupported *UnsupportedFormatError
+	return errors.As(err, &tooLarge) || errors.As(err, &tooManyPixels) || errors.As(err, &unsupported)
+}
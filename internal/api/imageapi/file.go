@@ -0,0 +1,59 @@
+package imageapi
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileImageProvider is an ImageProvider that always returns the same local image file.
+type fileImageProvider struct {
+	path  string
+	image image.Image
+}
+
+// NewFileImageProvider creates an ImageProvider that serves the JPEG, PNG or GIF image at path,
+// scaled and cropped to the requested size.
+func NewFileImageProvider(path string) (ImageProvider, error) {
+	img, err := decodeImageFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileImageProvider{path: path, image: img}, nil
+}
+
+// GetRandomImage returns the image file with the requested size and filters applied.
+func (p *fileImageProvider) GetRandomImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	config := imgCnfg.Build()
+	img := applyFilters(fillImage(p.image, config.Width, config.Height), config.Filters)
+	return withMetadata(img, ImageMetadata{ID: imageName(p.path), Source: p.path}), nil
+}
+
+// decodeImageFile opens and decodes the image file at path.
+func decodeImageFile(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image file: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, &DecodeError{Err: err}
+	}
+	return img, nil
+}
+
+// imageName returns the file name of path without its extension.
+func imageName(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
@@ -0,0 +1,99 @@
+package imageapi
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePNG(t *testing.T, path string, img image.Image) {
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	assert.NoError(t, png.Encode(file, img))
+}
+
+func TestFileImageProvider_ScalesAndFilters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "red.png")
+	writePNG(t, path, gradientImage(40, 20, false, 0))
+
+	provider, err := NewFileImageProvider(path)
+	assert.NoError(t, err)
+
+	img, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder().WithWidth(10).WithHeight(10).WithFilters(ImageFilters{"grayscale"}))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 10, 10), img.Bounds())
+	metadata, _ := MetadataOf(img)
+	assert.Equal(t, "red", metadata.ID)
+	_, isGray := img.(*MetadataImage).Image.(*image.Gray)
+	assert.True(t, isGray, "Expected grayscale filter to be applied")
+}
+
+func TestFileImageProvider_MissingFile(t *testing.T) {
+	_, err := NewFileImageProvider(filepath.Join(t.TempDir(), "missing.png"))
+	assert.Error(t, err)
+}
+
+func TestDirImageProvider_PinnedAndSeeded(t *testing.T) {
+	dir := t.TempDir()
+	for name, c := range map[string]color.RGBA{"red": {255, 0, 0, 255}, "blue": {0, 0, 255, 255}} {
+		img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		for i := range img.Pix {
+			img.Pix[i] = []uint8{c.R, c.G, c.B, c.A}[i%4]
+		}
+		writePNG(t, filepath.Join(dir, name+".png"), img)
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+
+	provider, err := NewDirImageProvider(dir)
+	assert.NoError(t, err)
+
+	img, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder().WithWidth(4).WithHeight(4).WithImageID("blue"))
+	assert.NoError(t, err)
+	r, g, b, _ := img.At(0, 0).RGBA()
+	assert.Equal(t, []uint32{0, 0, 0xffff}, []uint32{r, g, b}, "Expected the pinned image")
+
+	first, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder().WithSeed("seed"))
+	assert.NoError(t, err)
+	second, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder().WithSeed("seed"))
+	assert.NoError(t, err)
+	firstMetadata, _ := MetadataOf(first)
+	secondMetadata, _ := MetadataOf(second)
+	assert.Equal(t, firstMetadata.ID, secondMetadata.ID, "Expected the same seed to pick the same image")
+
+	_, err = provider.GetRandomImage(context.Background(), NewImageConfigBuilder().WithImageID("green"))
+	assert.Error(t, err, "Expected error for an unknown image")
+}
+
+func TestDirImageProvider_EmptyDir(t *testing.T) {
+	provider, err := NewDirImageProvider(t.TempDir())
+	assert.NoError(t, err)
+	_, err = provider.GetRandomImage(context.Background(), NewImageConfigBuilder())
+	assert.Error(t, err, "Expected error for a directory without images")
+}
+
+func TestDirImageProvider_SearchByFileName(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"mountain-lake", "ocean-sunset", "city-night"} {
+		writePNG(t, filepath.Join(dir, name+".png"), image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	}
+
+	provider, err := NewDirImageProvider(dir)
+	assert.NoError(t, err)
+	searcher, ok := provider.(ImageSearcher)
+	assert.True(t, ok, "Expected the directory provider to support search")
+
+	img, err := searcher.SearchImage(context.Background(), NewImageConfigBuilder().WithKeywords([]string{"Sunset", "mountain", "ocean"}))
+	assert.NoError(t, err)
+	metadata, _ := MetadataOf(img)
+	assert.Equal(t, "ocean-sunset", metadata.ID, "Expected the image matching the most keywords")
+
+	_, err = searcher.SearchImage(context.Background(), NewImageConfigBuilder().WithKeywords([]string{"desert"}))
+	assert.ErrorIs(t, err, ErrNoSearchMatch)
+}
@@ -0,0 +1,64 @@
+package imageapi
+
+import (
+	"context"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+// generatedImageProvider is an ImageProvider that draws gradient images without any network access.
+type generatedImageProvider struct{}
+
+// NewGeneratedImageProvider creates an ImageProvider that draws a linear gradient between two
+// colours at a random angle. A configured seed always produces the same image.
+func NewGeneratedImageProvider() ImageProvider {
+	return generatedImageProvider{}
+}
+
+// GetRandomImage draws a gradient image with the requested size and filters applied.
+func (generatedImageProvider) GetRandomImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	config := imgCnfg.Build()
+
+	seed := config.Seed
+	if seed == "" {
+		seed = strconv.FormatInt(rand.Int63(), 36)
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(seed))
+	rng := rand.New(rand.NewSource(int64(hash.Sum64())))
+
+	from := color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255}
+	to := color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255}
+	angle := rng.Float64() * 2 * math.Pi
+
+	img := drawGradient(max(config.Width, 1), max(config.Height, 1), from, to, angle)
+	return withMetadata(applyFilters(img, config.Filters), ImageMetadata{ID: seed, Source: "generated"}), nil
+}
+
+// drawGradient draws a linear gradient from one colour to another along the given angle.
+func drawGradient(width, height int, from, to color.RGBA, angle float64) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	dx, dy := math.Cos(angle), math.Sin(angle)
+	// Project the corners onto the gradient direction to normalise positions to [0, 1].
+	extent := math.Abs(dx)*float64(width) + math.Abs(dy)*float64(height)
+	cx, cy := float64(width)/2, float64(height)/2
+
+	lerp := func(a, b uint8, t float64) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*t)
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			t := ((float64(x)-cx)*dx+(float64(y)-cy)*dy)/extent + 0.5
+			t = math.Max(0, math.Min(1, t))
+			img.SetRGBA(x, y, color.RGBA{lerp(from.R, to.R, t), lerp(from.G, to.G, t), lerp(from.B, to.B, t), 255})
+		}
+	}
+	return img
+}
@@ -0,0 +1,31 @@
+package imageapi
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedImageProvider_DeterministicSeed(t *testing.T) {
+	provider := NewGeneratedImageProvider()
+
+	first, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder().WithWidth(32).WithHeight(16).WithSeed("kiosk"))
+	assert.NoError(t, err)
+	second, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder().WithWidth(32).WithHeight(16).WithSeed("kiosk"))
+	assert.NoError(t, err)
+	other, err := provider.GetRandomImage(context.Background(), NewImageConfigBuilder().WithWidth(32).WithHeight(16).WithSeed("other"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, image.Rect(0, 0, 32, 16), first.Bounds())
+	assert.Equal(t, first, second, "Expected the same seed to generate the same image")
+	assert.NotEqual(t, first, other, "Expected different seeds to generate different images")
+}
+
+func TestGeneratedImageProvider_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewGeneratedImageProvider().GetRandomImage(ctx, NewImageConfigBuilder())
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,34 @@
+package imageapi
+
+import (
+	"image"
+)
+
+// ImageMetadata describes where a fetched image came from and how it hashes.
+type ImageMetadata struct {
+	ID     string
+	Source string
+	Hash   uint64
+}
+
+// MetadataImage is an image.Image that carries the metadata of the fetched image.
+type MetadataImage struct {
+	image.Image
+	Metadata ImageMetadata
+}
+
+// MetadataOf returns the metadata attached to img, if any.
+func MetadataOf(img image.Image) (ImageMetadata, bool) {
+	if withMetadata, ok := img.(*MetadataImage); ok {
+		return withMetadata.Metadata, true
+	}
+	return ImageMetadata{}, false
+}
+
+// withMetadata returns img with the given metadata attached, replacing any existing metadata.
+func withMetadata(img image.Image, metadata ImageMetadata) *MetadataImage {
+	if existing, ok := img.(*MetadataImage); ok {
+		img = existing.Image
+	}
+	return &MetadataImage{Image: img, Metadata: metadata}
+}
@@ -0,0 +1,88 @@
+package imageapi
+
+import (
+	"image"
+	"math/bits"
+)
+
+const (
+	// hashSize is the side length of the grid sampled by the perceptual hashes (8x8 = 64 bits).
+	hashSize = 8
+	// maxSamplesPerCell bounds how many source pixels are averaged into one grid cell.
+	maxSamplesPerCell = 16
+)
+
+// AverageHash computes the 64-bit average hash (aHash) of img: each bit is set when
+// the corresponding cell of an 8x8 grayscale thumbnail is brighter than the mean.
+func AverageHash(img image.Image) uint64 {
+	cells := grayscaleThumbnail(img, hashSize, hashSize)
+
+	var mean float64
+	for _, v := range cells {
+		mean += v
+	}
+	mean /= float64(len(cells))
+
+	var hash uint64
+	for i, v := range cells {
+		if v > mean {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// DifferenceHash computes the 64-bit difference hash (dHash) of img: each bit is set when
+// a cell of a 9x8 grayscale thumbnail is brighter than its right-hand neighbour.
+func DifferenceHash(img image.Image) uint64 {
+	cells := grayscaleThumbnail(img, hashSize+1, hashSize)
+
+	var hash uint64
+	for y := 0; y < hashSize; y++ {
+		for x := 0; x < hashSize; x++ {
+			row := y * (hashSize + 1)
+			if cells[row+x] > cells[row+x+1] {
+				hash |= 1 << uint(y*hashSize+x)
+			}
+		}
+	}
+	return hash
+}
+
+// HammingDistance returns the number of differing bits between two perceptual hashes.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// grayscaleThumbnail downsamples img to a width x height grid of luminance values
+// by averaging a bounded number of evenly spaced samples inside each cell.
+func grayscaleThumbnail(img image.Image, width, height int) []float64 {
+	bounds := img.Bounds()
+	cells := make([]float64, width*height)
+	if bounds.Empty() {
+		return cells
+	}
+
+	for cy := 0; cy < height; cy++ {
+		y0 := bounds.Min.Y + cy*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(cy+1)*bounds.Dy()/height, y0+1)
+		stepY := max((y1-y0)/maxSamplesPerCell, 1)
+		for cx := 0; cx < width; cx++ {
+			x0 := bounds.Min.X + cx*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(cx+1)*bounds.Dx()/width, x0+1)
+			stepX := max((x1-x0)/maxSamplesPerCell, 1)
+
+			var sum float64
+			var count int
+			for y := y0; y < y1; y += stepY {
+				for x := x0; x < x1; x += stepX {
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					count++
+				}
+			}
+			cells[cy*width+cx] = sum / float64(count)
+		}
+	}
+	return cells
+}
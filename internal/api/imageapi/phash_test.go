@@ -0,0 +1,50 @@
+package imageapi
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gradientImage returns an image whose brightness increases left to right, or right to left when reversed.
+func gradientImage(width, height int, reversed bool, offset uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8(x * 200 / width)
+			if reversed {
+				v = 200 - v
+			}
+			v += offset
+			img.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	return img
+}
+
+func TestDifferenceHash_SimilarImages(t *testing.T) {
+	original := gradientImage(64, 48, false, 0)
+	brighter := gradientImage(64, 48, false, 20)
+	reversed := gradientImage(64, 48, true, 0)
+
+	assert.Equal(t, 0, HammingDistance(DifferenceHash(original), DifferenceHash(original)))
+	assert.LessOrEqual(t, HammingDistance(DifferenceHash(original), DifferenceHash(brighter)), DefaultDedupThreshold)
+	assert.Greater(t, HammingDistance(DifferenceHash(original), DifferenceHash(reversed)), DefaultDedupThreshold)
+}
+
+func TestAverageHash_SimilarImages(t *testing.T) {
+	original := gradientImage(64, 48, false, 0)
+	resized := gradientImage(128, 96, false, 0)
+	reversed := gradientImage(64, 48, true, 0)
+
+	assert.LessOrEqual(t, HammingDistance(AverageHash(original), AverageHash(resized)), DefaultDedupThreshold)
+	assert.Greater(t, HammingDistance(AverageHash(original), AverageHash(reversed)), DefaultDedupThreshold)
+}
+
+func TestHammingDistance(t *testing.T) {
+	assert.Equal(t, 0, HammingDistance(0xff, 0xff))
+	assert.Equal(t, 8, HammingDistance(0xff, 0x00))
+	assert.Equal(t, 64, HammingDistance(0, ^uint64(0)))
+}
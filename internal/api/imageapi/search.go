@@ -0,0 +1,17 @@
+package imageapi
+
+import (
+	"context"
+	"errors"
+	"image"
+)
+
+// ErrNoSearchMatch is returned by an ImageSearcher when no image matches the keywords.
+var ErrNoSearchMatch = errors.New("no image matches the search keywords")
+
+// ImageSearcher is an optional interface for image providers that can find an image
+// matching the keywords of the configuration, set with ImageConfigBuilder.WithKeywords.
+// Providers without it are used for random images only.
+type ImageSearcher interface {
+	SearchImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error)
+}
@@ -0,0 +1,199 @@
+package imageapi
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/ramyad/tucows/internal/logging"
+)
+
+const (
+	// Placeholders that are always available in a URL template.
+	PlaceholderWidth  = "width"
+	PlaceholderHeight = "height"
+	PlaceholderSeed   = "seed"
+)
+
+// placeholderPattern matches {name} placeholders in a URL template.
+var placeholderPattern = regexp.MustCompile(`\{([a-z0-9_]+)\}`)
+
+// FilterParam maps an image filter to the values substituted for its placeholder
+// when the filter is requested (On) or not requested (Off).
+type FilterParam struct {
+	On  string `json:"on"`
+	Off string `json:"off"`
+}
+
+// TemplateConfig describes an HTTP image source whose request URL is built from a template
+// such as "https://host/img?w={width}&h={height}&seed={seed}&gray={grayscale}".
+type TemplateConfig struct {
+	URLTemplate      string                 `json:"url_template"`
+	Filters          map[string]FilterParam `json:"filters"`
+	Headers          map[string]string      `json:"headers"`
+	MaxResponseBytes int64                  `json:"max_response_bytes"`
+	MaxPixels        int                    `json:"max_pixels"`
+}
+
+// TemplateImageAPIBuilder provides methods for building a templateImageAPI instance.
+type TemplateImageAPIBuilder struct {
+	config TemplateConfig
+	logger *slog.Logger
+}
+
+// templateImageAPI is an ImageProvider that fetches images from a URL template.
+type templateImageAPI struct {
+	urlTemplate      string
+	filters          map[string]FilterParam
+	header           http.Header
+	maxResponseBytes int64
+	maxPixels        int
+	logger           *slog.Logger
+}
+
+// NewTemplateImageAPIBuilder creates a new TemplateImageAPIBuilder for the given URL template.
+func NewTemplateImageAPIBuilder(urlTemplate string) *TemplateImageAPIBuilder {
+	return NewTemplateImageAPIBuilderFromConfig(TemplateConfig{URLTemplate: urlTemplate})
+}
+
+// NewTemplateImageAPIBuilderFromConfig creates a new TemplateImageAPIBuilder from a TemplateConfig,
+// allowing image sources to be described entirely by configuration.
+func NewTemplateImageAPIBuilderFromConfig(config TemplateConfig) *TemplateImageAPIBuilder {
+	builder := &TemplateImageAPIBuilder{
+		config: TemplateConfig{
+			URLTemplate:      config.URLTemplate,
+			Filters:          map[string]FilterParam{},
+			Headers:          map[string]string{},
+			MaxResponseBytes: config.MaxResponseBytes,
+			MaxPixels:        config.MaxPixels,
+		},
+	}
+	for filter, param := range config.Filters {
+		builder.config.Filters[filter] = param
+	}
+	for key, value := range config.Headers {
+		builder.config.Headers[key] = value
+	}
+	return builder
+}
+
+// WithFilterParam maps a filter to the values substituted for its {filter} placeholder and returns the builder instance.
+func (tab *TemplateImageAPIBuilder) WithFilterParam(filter, on, off string) *TemplateImageAPIBuilder {
+	tab.config.Filters[filter] = FilterParam{On: on, Off: off}
+	return tab
+}
+
+// WithHeader sets a header sent with every image request and returns the builder instance.
+func (tab *TemplateImageAPIBuilder) WithHeader(key, value string) *TemplateImageAPIBuilder {
+	tab.config.Headers[key] = value
+	return tab
+}
+
+// WithBearerToken sets a bearer token Authorization header and returns the builder instance.
+func (tab *TemplateImageAPIBuilder) WithBearerToken(token string) *TemplateImageAPIBuilder {
+	return tab.WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithMaxResponseBytes sets the maximum number of bytes read from an image response and returns the builder instance.
+func (tab *TemplateImageAPIBuilder) WithMaxResponseBytes(n int64) *TemplateImageAPIBuilder {
+	tab.config.MaxResponseBytes = n
+	return tab
+}
+
+// WithMaxPixels sets the maximum pixel count accepted before decoding and returns the builder instance.
+func (tab *TemplateImageAPIBuilder) WithMaxPixels(n int) *TemplateImageAPIBuilder {
+	tab.config.MaxPixels = n
+	return tab
+}
+
+// WithLogger sets the logger for request failures and returns the builder instance.
+func (tab *TemplateImageAPIBuilder) WithLogger(logger *slog.Logger) *TemplateImageAPIBuilder {
+	tab.logger = logger
+	return tab
+}
+
+// Build validates the template and constructs an ImageProvider.
+// Every placeholder must be width, height, seed or a mapped filter.
+func (tab *TemplateImageAPIBuilder) Build() (ImageProvider, error) {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(tab.config.URLTemplate, -1) {
+		name := match[1]
+		if name == PlaceholderWidth || name == PlaceholderHeight || name == PlaceholderSeed {
+			continue
+		}
+		if _, ok := tab.config.Filters[name]; !ok {
+			return nil, fmt.Errorf("unknown placeholder {%s} in URL template", name)
+		}
+	}
+	if _, err := url.Parse(placeholderPattern.ReplaceAllString(tab.config.URLTemplate, "0")); err != nil {
+		return nil, fmt.Errorf("invalid URL template: %w", err)
+	}
+
+	api := &templateImageAPI{
+		urlTemplate:      tab.config.URLTemplate,
+		filters:          map[string]FilterParam{},
+		header:           http.Header{},
+		maxResponseBytes: tab.config.MaxResponseBytes,
+		maxPixels:        tab.config.MaxPixels,
+		logger:           logging.OrDefault(tab.logger).With(logging.ProviderKey, "template"),
+	}
+	for filter, param := range tab.config.Filters {
+		api.filters[filter] = param
+	}
+	for key, value := range tab.config.Headers {
+		api.header.Set(key, value)
+	}
+	if api.maxResponseBytes <= 0 {
+		api.maxResponseBytes = DefaultMaxResponseBytes
+	}
+	if api.maxPixels <= 0 {
+		api.maxPixels = DefaultMaxImagePixels
+	}
+	return api, nil
+}
+
+// GetRandomImage fetches an image from the URL produced by expanding the template with the provided configuration.
+func (api *templateImageAPI) GetRandomImage(ctx context.Context, imgCnfg *ImageConfigBuilder) (image.Image, error) {
+	path := api.buildPath(imgCnfg.Build())
+	return fetchImage(ctx, fetchRequest{
+		path:      path,
+		header:    api.header,
+		maxBytes:  api.maxResponseBytes,
+		maxPixels: api.maxPixels,
+		attempts:  RetryAttempts,
+		logger:    api.logger,
+		formats:   templateFormats,
+	})
+}
+
+// buildPath expands the URL template with query-escaped values from the configuration.
+// A random seed is used when the configuration does not specify one.
+func (api *templateImageAPI) buildPath(imgCnfg ImageConfig) string {
+	seed := imgCnfg.Seed
+	if seed == "" {
+		seed = strconv.FormatInt(rand.Int63(), 36)
+	}
+
+	values := map[string]string{
+		PlaceholderWidth:  strconv.Itoa(imgCnfg.Width),
+		PlaceholderHeight: strconv.Itoa(imgCnfg.Height),
+		PlaceholderSeed:   seed,
+	}
+	for filter, param := range api.filters {
+		values[filter] = param.Off
+	}
+	for _, filter := range imgCnfg.Filters {
+		if param, ok := api.filters[filter]; ok {
+			values[filter] = param.On
+		}
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(api.urlTemplate, func(placeholder string) string {
+		return url.QueryEscape(values[placeholder[1:len(placeholder)-1]])
+	})
+}
@@ -0,0 +1,74 @@
+package imageapi
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateImageAPIBuilder_UnknownPlaceholder(t *testing.T) {
+	_, err := NewTemplateImageAPIBuilder("https://host/img?w={width}&q={quality}").Build()
+	assert.Error(t, err, "Expected error for unmapped placeholder")
+}
+
+func TestTemplateBuildPath(t *testing.T) {
+	api, err := NewTemplateImageAPIBuilder("https://host/img?w={width}&h={height}&seed={seed}&gray={grayscale}&blur={blur}").
+		WithFilterParam(ImageFilterGrayscale, "1", "0").
+		WithFilterParam(ImageFilterBlur, "5", "").
+		Build()
+	assert.NoError(t, err)
+
+	ImageConfig := NewImageConfigBuilder().WithWidth(300).WithHeight(200).WithSeed("a b").WithFilters(ImageFilters{"grayscale"}).Build()
+	expectedPath := "https://host/img?w=300&h=200&seed=a+b&gray=1&blur="
+	resultPath := api.(*templateImageAPI).buildPath(ImageConfig)
+	assert.Equal(t, expectedPath, resultPath, "Path built with incorrect format")
+}
+
+func TestTemplateGetRandomImage_SendsHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 6)), nil))
+
+	var gotAuth, gotWidth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotWidth = r.URL.Query().Get("w")
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	api, err := NewTemplateImageAPIBuilderFromConfig(TemplateConfig{
+		URLTemplate: server.URL + "/img?w={width}&h={height}",
+		Headers:     map[string]string{"Authorization": "Bearer secret"},
+	}).Build()
+	assert.NoError(t, err)
+
+	img, err := api.GetRandomImage(context.Background(), NewImageConfigBuilder().WithWidth(8).WithHeight(6))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 8, 6), img.Bounds())
+	assert.Equal(t, "Bearer secret", gotAuth)
+	assert.Equal(t, "8", gotWidth)
+}
+
+func TestTemplateGetRandomImage_AcceptsPNG(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 6))))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	api, err := NewTemplateImageAPIBuilder(server.URL + "/img.png").Build()
+	assert.NoError(t, err)
+
+	img, err := api.GetRandomImage(context.Background(), NewImageConfigBuilder().WithWidth(8).WithHeight(6))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 8, 6), img.Bounds())
+}
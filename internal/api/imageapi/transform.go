@@ -0,0 +1,85 @@
+package imageapi
+
+import (
+	"image"
+	"image/color"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// blurRadius is the box blur radius applied by the blur filter to locally produced images.
+const blurRadius = 3
+
+// fillImage scales img to cover a width x height canvas, cropping the overflow around the centre.
+func fillImage(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	if width <= 0 || height <= 0 || bounds.Empty() {
+		return img
+	}
+	if bounds.Dx() == width && bounds.Dy() == height {
+		return img
+	}
+
+	scale := max(float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+	srcWidth := int(float64(width) / scale)
+	srcHeight := int(float64(height) / scale)
+	srcX := bounds.Min.X + (bounds.Dx()-srcWidth)/2
+	srcY := bounds.Min.Y + (bounds.Dy()-srcHeight)/2
+	src := image.Rect(srcX, srcY, srcX+srcWidth, srcY+srcHeight)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, src, xdraw.Src, nil)
+	return dst
+}
+
+// applyFilters applies the supported filters to a locally produced image.
+func applyFilters(img image.Image, filters ImageFilters) image.Image {
+	for _, filter := range filters {
+		switch filter {
+		case ImageFilterGrayscale:
+			img = grayscale(img)
+		case ImageFilterBlur:
+			img = boxBlur(img, blurRadius)
+		}
+	}
+	return img
+}
+
+// grayscale returns a grayscale copy of img.
+func grayscale(img image.Image) image.Image {
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray.Set(x, y, color.GrayModel.Convert(img.At(x, y)))
+		}
+	}
+	return gray
+}
+
+// boxBlur returns a copy of img blurred with a square kernel of the given radius.
+func boxBlur(img image.Image, radius int) image.Image {
+	bounds := img.Bounds()
+	src := image.NewRGBA(bounds)
+	xdraw.Draw(src, bounds, img, bounds.Min, xdraw.Src)
+	dst := image.NewRGBA(bounds)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var r, g, b, a, n uint32
+			for dy := -radius; dy <= radius; dy++ {
+				for dx := -radius; dx <= radius; dx++ {
+					p := image.Pt(x+dx, y+dy)
+					if !p.In(bounds) {
+						continue
+					}
+					c := src.RGBAAt(p.X, p.Y)
+					r, g, b, a = r+uint32(c.R), g+uint32(c.G), b+uint32(c.B), a+uint32(c.A)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), uint8(a / n)})
+		}
+	}
+	return dst
+}
@@ -2,15 +2,19 @@
 package quoteapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/avast/retry-go"
+	"github.com/ramyad/tucows/internal/logging"
 	"github.com/ramyad/tucows/internal/shared"
 )
 
@@ -25,6 +29,8 @@ const (
 	MaxKeyValue     = 999999
 	RetryAttempts   = 4
 	RetryDelay      = time.Second
+	// drainLimit bounds how much of a response body is discarded to allow connection reuse.
+	drainLimit = 64 << 10
 )
 
 // QuoteApiBuilder provides methods for building a quoteAPI instance.
@@ -38,20 +44,34 @@ type quoteAPI struct {
 	method   string
 	format   string
 	language string
+	// retryAttempts falls back to RetryAttempts when zero.
+	retryAttempts int
+	logger        *slog.Logger
 }
 
 // QuoteProvider is an interface that defines the contract for fetching random quote.
 type QuoteProvider interface {
-	GetRandomQuote(qtCnfgBldr *QuoteConfigBuilder) (string, error)
+	GetRandomQuote(ctx context.Context, qtCnfgBldr *QuoteConfigBuilder) (string, error)
 }
 
-// QuoteConfigBuilder provides methods for building a quoteConfig instance.
+// Quote represents a quote together with its author, which may be empty.
+type Quote struct {
+	Text   string
+	Author string
+}
+
+// AuthorQuoteProvider is implemented by quote providers that also know the author of a quote.
+type AuthorQuoteProvider interface {
+	GetRandomQuoteWithAuthor(ctx context.Context, qtCnfgBldr *QuoteConfigBuilder) (Quote, error)
+}
+
+// QuoteConfigBuilder provides methods for building a QuoteConfig value.
 type QuoteConfigBuilder struct {
-	config quoteConfig
+	config QuoteConfig
 }
 
-// quoteConfig represents configuration options for fetching quotes.
-type quoteConfig struct {
+// QuoteConfig represents configuration options for fetching quotes.
+type QuoteConfig struct {
 	Key int
 }
 
@@ -91,6 +111,18 @@ func (tab *QuoteApiBuilder) WithLanguage(language string) *QuoteApiBuilder {
 	return tab
 }
 
+// WithRetryAttempts sets how many times a request is attempted before giving up and returns the builder instance.
+func (tab *QuoteApiBuilder) WithRetryAttempts(n int) *QuoteApiBuilder {
+	tab.api.retryAttempts = n
+	return tab
+}
+
+// WithLogger sets the logger for request failures and returns the builder instance.
+func (tab *QuoteApiBuilder) WithLogger(logger *slog.Logger) *QuoteApiBuilder {
+	tab.api.logger = logger
+	return tab
+}
+
 // Build constructs and returns a QuoteProvider interface.
 func (tab *QuoteApiBuilder) Build() QuoteProvider {
 	return tab.api
@@ -99,7 +131,7 @@ func (tab *QuoteApiBuilder) Build() QuoteProvider {
 // NewQuoteConfigBuilder creates a new QuoteConfigBuilder instance.
 func NewQuoteConfigBuilder() *QuoteConfigBuilder {
 	return &QuoteConfigBuilder{
-		config: quoteConfig{},
+		config: QuoteConfig{},
 	}
 }
 
@@ -109,64 +141,93 @@ func (tcb *QuoteConfigBuilder) WithKey(key int) *QuoteConfigBuilder {
 	return tcb
 }
 
-// Build constructs and returns a quoteConfig instance.
-func (tcb *QuoteConfigBuilder) Build() quoteConfig {
+// Build constructs and returns a QuoteConfig value.
+func (tcb *QuoteConfigBuilder) Build() QuoteConfig {
 	return tcb.config
 }
 
 // Data represents the structure of the JSON response data containing a quote.
 type Data struct {
-	QuoteText string `json:"quoteText"`
+	QuoteText   string `json:"quoteText"`
+	QuoteAuthor string `json:"quoteAuthor"`
 }
 
 // GetRandomQuote fetches a random quote using the provided configuration from the quote API.
-func (api *quoteAPI) GetRandomQuote(qtCnfgBldr *QuoteConfigBuilder) (string, error) {
+func (api *quoteAPI) GetRandomQuote(ctx context.Context, qtCnfgBldr *QuoteConfigBuilder) (string, error) {
+	quote, err := api.GetRandomQuoteWithAuthor(ctx, qtCnfgBldr)
+	if err != nil {
+		return "", err
+	}
+	return quote.Text, nil
+}
+
+// GetRandomQuoteWithAuthor fetches a random quote and its author using the provided configuration from the quote API.
+// Retries stop as soon as ctx is cancelled.
+func (api *quoteAPI) GetRandomQuoteWithAuthor(ctx context.Context, qtCnfgBldr *QuoteConfigBuilder) (Quote, error) {
 	data := &Data{}
 	path := api.buildPath(qtCnfgBldr.Build())
+	attempts := api.retryAttempts
+	if attempts <= 0 {
+		attempts = RetryAttempts
+	}
+	logger := logging.OrDefault(api.logger).With(logging.ProviderKey, "forismatic")
 
-	var resp *http.Response
+	attempt := 0
 	err := retry.Do(
 		func() error {
-			var err error
-			resp, err = http.Get(path)
+			attempt++
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 			if err != nil {
-				log.Printf("[%s] Get request Error: %v", shared.LogLevelError, err)
-				return err
+				return retry.Unrecoverable(err)
 			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				logger.ErrorContext(ctx, "Quote request failed", logging.AttemptKey, attempt, logging.Err(err))
+				return shared.RequestError(err)
+			}
+			defer drainAndClose(resp.Body)
 
 			if resp.StatusCode != http.StatusOK {
-				log.Printf("[%s] Received non-200 status code: %d", shared.LogLevelError, resp.StatusCode)
-				return fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+				logger.ErrorContext(ctx, "Received non-200 status code", logging.AttemptKey, attempt, "status", resp.StatusCode)
+				return shared.StatusError(resp.StatusCode)
 			}
 
 			err = json.NewDecoder(resp.Body).Decode(data)
 			if err != nil {
-				log.Printf("[%s] JSON Parse Error: %v", shared.LogLevelError, err)
-				return fmt.Errorf("JSON Parse Error: %w", err)
+				logger.ErrorContext(ctx, "Failed to parse quote response", logging.AttemptKey, attempt, logging.Err(err))
+				return shared.Wrap(shared.ErrDecode, fmt.Errorf("failed to parse quote response: %w", err))
 			}
 
 			return nil
 		},
-		retry.Attempts(RetryAttempts),
+		retry.Context(ctx),
+		retry.Attempts(uint(attempts)),
 		retry.DelayType(retry.BackOffDelay),
 		retry.Delay(RetryDelay),
 		retry.OnRetry(func(n uint, err error) {
-			if n == uint(RetryAttempts-1) {
-				log.Printf("[%s] Warning: Reached max retry attempts - 1.", shared.LogLevelWarning)
+			if n == uint(attempts-1) {
+				logger.WarnContext(ctx, "Retrying quote request for the last time", logging.AttemptKey, n+1)
 			}
 		}),
 	)
 
 	if err != nil {
-		log.Printf("[%s] Failed to get image from random quote API after retries: %v", shared.LogLevelError, err)
-		return "", fmt.Errorf("failed to get image from random quote API after retries: %s", err)
+		logger.ErrorContext(ctx, "Failed to get quote after retries", logging.Err(err))
+		return Quote{}, fmt.Errorf("failed to get quote from random quote API after retries: %w", shared.FlattenRetryError(err))
 	}
 
-	return data.QuoteText, nil
+	return Quote{Text: data.QuoteText, Author: strings.TrimSpace(data.QuoteAuthor)}, nil
+}
+
+// drainAndClose discards a bounded amount of the remaining body so the
+// underlying connection can be reused, then closes it.
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, drainLimit))
+	body.Close()
 }
 
 // buildPath constructs the URL path for fetching a quote based on the provided configuration.
-func (api *quoteAPI) buildPath(txtcnfg quoteConfig) string {
+func (api *quoteAPI) buildPath(txtcnfg QuoteConfig) string {
 	baseURL, _ := url.Parse(api.baseURL)
 	query := url.Values{
 		"method": []string{api.method},
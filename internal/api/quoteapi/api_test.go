@@ -1,37 +1,96 @@
 package quoteapi
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
+	"github.com/ramyad/tucows/internal/shared"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetRandomQuote_success(t *testing.T) {
 	quoteAPI := NewQuoteApiBuilder().Build()
-	quoteConfig := NewQuoteConfigBuilder().WithKey(100)
+	QuoteConfig := NewQuoteConfigBuilder().WithKey(100)
 
-	result, err := quoteAPI.GetRandomQuote(quoteConfig)
+	result, err := quoteAPI.GetRandomQuote(context.Background(), QuoteConfig)
 	assert.NoError(t, err, "Expected no error from GetRandomQuote")
 	assert.NotEmpty(t, result, "Expected a non-empty quote result")
 }
 
 func TestGetRandomQuote_Error(t *testing.T) {
 	quoteAPI := NewQuoteApiBuilder().WithBaseURL("http://unavailable.api").Build()
-	quoteConfig := NewQuoteConfigBuilder()
-	expectedError := fmt.Errorf("failed to get image from random quote API after retries")
+	QuoteConfig := NewQuoteConfigBuilder()
+	expectedError := fmt.Errorf("failed to get quote from random quote API after retries")
 
-	_, err := quoteAPI.GetRandomQuote(quoteConfig)
+	_, err := quoteAPI.GetRandomQuote(context.Background(), QuoteConfig)
 	assert.Error(t, err, "Expected error due to unavailable API")
 	assert.Contains(t, err.Error(), expectedError.Error(), "Expected error message mismatch")
+	assert.ErrorIs(t, err, shared.ErrUpstreamUnavailable)
+}
+
+func TestGetRandomQuote_ErrorKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{"server error", http.StatusServiceUnavailable, "", shared.ErrUpstreamUnavailable},
+		{"rate limited", http.StatusTooManyRequests, "", shared.ErrRateLimited},
+		{"not found", http.StatusNotFound, "", shared.ErrUpstreamBadResponse},
+		{"invalid JSON", http.StatusOK, "{", shared.ErrDecode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			quoteAPI := NewQuoteApiBuilder().WithBaseURL(server.URL).WithRetryAttempts(1).Build()
+
+			_, err := quoteAPI.GetRandomQuote(context.Background(), NewQuoteConfigBuilder())
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Equal(t, tt.expected, shared.Kind(err))
+		})
+	}
+}
+
+func TestGetRandomQuote_closesResponseBodies(t *testing.T) {
+	var connections atomic.Int32
+	statuses := []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK}
+	var requests atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[requests.Add(1)-1])
+		w.Write([]byte(`{"quoteText": "Stay calm", "quoteAuthor": "Someone"}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+	quoteAPI := NewQuoteApiBuilder().WithBaseURL(server.URL).WithRetryAttempts(1).Build()
+
+	for range statuses {
+		quoteAPI.GetRandomQuote(context.Background(), NewQuoteConfigBuilder())
+	}
+	assert.Equal(t, int32(1), connections.Load(), "Closed bodies must let every request reuse the connection")
 }
 
 func TestBuildPath(t *testing.T) {
 	api := NewQuoteApiBuilder().Build()
-	quoteConfig := NewQuoteConfigBuilder().WithKey(100).Build()
+	QuoteConfig := NewQuoteConfigBuilder().WithKey(100).Build()
 	expectedPath := "http://api.forismatic.com/api/1.0/?format=json&key=100&lang=en&method=getQuote"
 
-	result := api.(*quoteAPI).buildPath(quoteConfig)
+	result := api.(*quoteAPI).buildPath(QuoteConfig)
 	assert.Equal(t, expectedPath, result, "Path built with incorrect format")
 }
 
@@ -0,0 +1,90 @@
+package quoteapi
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+// authorSeparator separates a quote from its author on a line of a quote file.
+const authorSeparator = " — "
+
+// DefaultQuotes is the built-in list of quotes served without any network access.
+var DefaultQuotes = []Quote{
+	{Text: "The best way out is always through.", Author: "Robert Frost"},
+	{Text: "What we think, we become.", Author: "Buddha"},
+	{Text: "Simplicity is the ultimate sophistication.", Author: "Leonardo da Vinci"},
+	{Text: "It always seems impossible until it's done.", Author: "Nelson Mandela"},
+	{Text: "Well done is better than well said.", Author: "Benjamin Franklin"},
+	{Text: "The journey of a thousand miles begins with one step.", Author: "Lao Tzu"},
+	{Text: "Turn your wounds into wisdom.", Author: "Oprah Winfrey"},
+	{Text: "Nothing will work unless you do.", Author: "Maya Angelou"},
+}
+
+// staticQuoteProvider is a QuoteProvider that serves quotes from an in-memory list.
+type staticQuoteProvider struct {
+	quotes []Quote
+}
+
+// NewStaticQuoteProvider creates a QuoteProvider that serves the given quotes.
+// A positive key always selects the same quote, otherwise a random quote is returned.
+func NewStaticQuoteProvider(quotes []Quote) (QuoteProvider, error) {
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("no quotes provided")
+	}
+	return &staticQuoteProvider{quotes: append([]Quote(nil), quotes...)}, nil
+}
+
+// NewFileQuoteProvider creates a QuoteProvider that serves the quotes in a text file, one per line.
+// An author may follow the quote after " — "; blank lines and lines starting with # are ignored.
+func NewFileQuoteProvider(path string) (QuoteProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open quote file: %w", err)
+	}
+	defer file.Close()
+
+	var quotes []Quote
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		quote := Quote{Text: line}
+		if i := strings.LastIndex(line, authorSeparator); i > 0 {
+			quote = Quote{Text: strings.TrimSpace(line[:i]), Author: strings.TrimSpace(line[i+len(authorSeparator):])}
+		}
+		quotes = append(quotes, quote)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read quote file: %w", err)
+	}
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("no quotes found in %s", path)
+	}
+	return NewStaticQuoteProvider(quotes)
+}
+
+// GetRandomQuote returns a quote from the list.
+func (p *staticQuoteProvider) GetRandomQuote(ctx context.Context, qtCnfgBldr *QuoteConfigBuilder) (string, error) {
+	quote, err := p.GetRandomQuoteWithAuthor(ctx, qtCnfgBldr)
+	if err != nil {
+		return "", err
+	}
+	return quote.Text, nil
+}
+
+// GetRandomQuoteWithAuthor returns a quote and its author from the list.
+func (p *staticQuoteProvider) GetRandomQuoteWithAuthor(ctx context.Context, qtCnfgBldr *QuoteConfigBuilder) (Quote, error) {
+	if err := ctx.Err(); err != nil {
+		return Quote{}, err
+	}
+	if key := qtCnfgBldr.Build().Key; key > 0 {
+		return p.quotes[key%len(p.quotes)], nil
+	}
+	return p.quotes[rand.Intn(len(p.quotes))], nil
+}
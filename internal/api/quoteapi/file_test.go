@@ -0,0 +1,45 @@
+package quoteapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileQuoteProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.txt")
+	content := "# favourite quotes\nStay hungry, stay foolish. — Steve Jobs\n\nAnonymous wisdom\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	provider, err := NewFileQuoteProvider(path)
+	assert.NoError(t, err)
+
+	quote, err := provider.(AuthorQuoteProvider).GetRandomQuoteWithAuthor(context.Background(), NewQuoteConfigBuilder().WithKey(2))
+	assert.NoError(t, err)
+	assert.Equal(t, Quote{Text: "Stay hungry, stay foolish.", Author: "Steve Jobs"}, quote)
+
+	text, err := provider.GetRandomQuote(context.Background(), NewQuoteConfigBuilder().WithKey(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "Anonymous wisdom", text)
+}
+
+func TestFileQuoteProvider_EmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("# nothing here\n"), 0o644))
+
+	_, err := NewFileQuoteProvider(path)
+	assert.Error(t, err, "Expected error for a file without quotes")
+}
+
+func TestStaticQuoteProvider_Cancelled(t *testing.T) {
+	provider, err := NewStaticQuoteProvider(DefaultQuotes)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = provider.GetRandomQuote(ctx, NewQuoteConfigBuilder())
+	assert.ErrorIs(t, err, context.Canceled)
+}
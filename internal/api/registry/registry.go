@@ -0,0 +1,218 @@
+// Package registry provides named quote and image providers that can be selected by configuration.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/shared"
+)
+
+const (
+	DefaultQuoteProvider = "forismatic"
+	DefaultImageProvider = "picsum"
+	// specSeparator separates a provider name from its argument, as in "dir:/path/to/images".
+	specSeparator = ":"
+)
+
+// QuoteProviderFactory creates a quote provider from the argument of a provider spec, which may be empty.
+type QuoteProviderFactory func(arg string) (quoteapi.QuoteProvider, error)
+
+// ImageProviderFactory creates an image provider from the argument of a provider spec, which may be empty.
+type ImageProviderFactory func(arg string) (imageapi.ImageProvider, error)
+
+// Registry maps provider names to the factories that create them.
+type Registry struct {
+	mu     sync.RWMutex
+	quotes map[string]QuoteProviderFactory
+	images map[string]ImageProviderFactory
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		quotes: map[string]QuoteProviderFactory{},
+		images: map[string]ImageProviderFactory{},
+	}
+}
+
+// Settings tunes the built-in network providers. Zero values keep the provider defaults.
+type Settings struct {
+	QuoteRetryAttempts    int
+	ImageRetryAttempts    int
+	MaxImageResponseBytes int64
+	MaxImagePixels        int
+	Logger                *slog.Logger
+}
+
+// Default creates a Registry with the built-in providers:
+//
+//	quote: forismatic[:baseURL], file:path, builtin
+//	image: picsum[:baseURL], template:config.json, file:path, dir:path, generated
+func Default() *Registry {
+	return DefaultWithSettings(Settings{})
+}
+
+// DefaultWithSettings creates a Registry with the built-in providers tuned by settings.
+func DefaultWithSettings(settings Settings) *Registry {
+	r := NewRegistry()
+
+	r.RegisterQuoteProvider("forismatic", func(arg string) (quoteapi.QuoteProvider, error) {
+		builder := quoteapi.NewQuoteApiBuilder().WithRetryAttempts(settings.QuoteRetryAttempts).WithLogger(settings.Logger)
+		if arg != "" {
+			builder.WithBaseURL(arg)
+		}
+		return builder.Build(), nil
+	})
+	r.RegisterQuoteProvider("file", func(arg string) (quoteapi.QuoteProvider, error) {
+		return quoteapi.NewFileQuoteProvider(arg)
+	})
+	r.RegisterQuoteProvider("builtin", func(arg string) (quoteapi.QuoteProvider, error) {
+		return quoteapi.NewStaticQuoteProvider(quoteapi.DefaultQuotes)
+	})
+
+	r.RegisterImageProvider("picsum", func(arg string) (imageapi.ImageProvider, error) {
+		builder := imageapi.NewImageAPIBuilder().
+			WithRetryAttempts(settings.ImageRetryAttempts).
+			WithMaxResponseBytes(settings.MaxImageResponseBytes).
+			WithMaxPixels(settings.MaxImagePixels).
+			WithLogger(settings.Logger)
+		if arg != "" {
+			builder.WithBaseURL(arg)
+		}
+		return imageapi.NewDedupImageProviderBuilder(builder.Build()).WithLogger(settings.Logger).Build(), nil
+	})
+	r.RegisterImageProvider("template", func(arg string) (imageapi.ImageProvider, error) {
+		config, err := loadTemplateConfig(arg)
+		if err != nil {
+			return nil, err
+		}
+		return imageapi.NewTemplateImageAPIBuilderFromConfig(config).WithLogger(settings.Logger).Build()
+	})
+	r.RegisterImageProvider("file", func(arg string) (imageapi.ImageProvider, error) {
+		return imageapi.NewFileImageProvider(arg)
+	})
+	r.RegisterImageProvider("dir", func(arg string) (imageapi.ImageProvider, error) {
+		return imageapi.NewDirImageProvider(arg)
+	})
+	r.RegisterImageProvider("generated", func(arg string) (imageapi.ImageProvider, error) {
+		return imageapi.NewGeneratedImageProvider(), nil
+	})
+
+	return r
+}
+
+// ImageCatalog creates the catalog of the image provider described by spec, tuned by settings.
+// Only picsum has a catalog, served by the same base URL as its images; false is returned
+// for every other provider.
+func ImageCatalog(spec string, settings Settings) (imageapi.ImageCatalog, bool) {
+	name, arg := parseSpec(spec)
+	if name != "picsum" {
+		return nil, false
+	}
+	builder := imageapi.NewImageAPIBuilder().WithRetryAttempts(settings.ImageRetryAttempts).WithLogger(settings.Logger)
+	if arg != "" {
+		builder.WithBaseURL(arg)
+	}
+	return builder.BuildCatalog(), true
+}
+
+// RegisterQuoteProvider registers a quote provider factory under name, replacing any existing one.
+func (r *Registry) RegisterQuoteProvider(name string, factory QuoteProviderFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quotes[name] = factory
+}
+
+// RegisterImageProvider registers an image provider factory under name, replacing any existing one.
+func (r *Registry) RegisterImageProvider(name string, factory ImageProviderFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.images[name] = factory
+}
+
+// QuoteProvider creates the quote provider described by spec, in the form "name" or "name:arg".
+func (r *Registry) QuoteProvider(spec string) (quoteapi.QuoteProvider, error) {
+	name, arg := parseSpec(spec)
+
+	r.mu.RLock()
+	factory, ok := r.quotes[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, shared.InvalidInput("unknown quote provider %q, available: %s", name, strings.Join(r.QuoteProviderNames(), ", "))
+	}
+
+	provider, err := factory(arg)
+	if err != nil {
+		return nil, shared.InvalidInput("failed to create quote provider %q: %w", name, err)
+	}
+	return provider, nil
+}
+
+// ImageProvider creates the image provider described by spec, in the form "name" or "name:arg".
+func (r *Registry) ImageProvider(spec string) (imageapi.ImageProvider, error) {
+	name, arg := parseSpec(spec)
+
+	r.mu.RLock()
+	factory, ok := r.images[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, shared.InvalidInput("unknown image provider %q, available: %s", name, strings.Join(r.ImageProviderNames(), ", "))
+	}
+
+	provider, err := factory(arg)
+	if err != nil {
+		return nil, shared.InvalidInput("failed to create image provider %q: %w", name, err)
+	}
+	return provider, nil
+}
+
+// QuoteProviderNames returns the sorted names of the registered quote providers.
+func (r *Registry) QuoteProviderNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.quotes))
+	for name := range r.quotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ImageProviderNames returns the sorted names of the registered image providers.
+func (r *Registry) ImageProviderNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.images))
+	for name := range r.images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseSpec splits a provider spec into its name and optional argument.
+func parseSpec(spec string) (string, string) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(spec), specSeparator)
+	return strings.ToLower(name), arg
+}
+
+// loadTemplateConfig reads a JSON encoded imageapi.TemplateConfig from path.
+func loadTemplateConfig(path string) (imageapi.TemplateConfig, error) {
+	var config imageapi.TemplateConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read template config: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse template config: %w", err)
+	}
+	return config, nil
+}
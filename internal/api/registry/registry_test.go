@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault_ProviderNames(t *testing.T) {
+	r := Default()
+	assert.Equal(t, []string{"builtin", "file", "forismatic"}, r.QuoteProviderNames())
+	assert.Equal(t, []string{"dir", "file", "generated", "picsum", "template"}, r.ImageProviderNames())
+}
+
+func TestQuoteProvider_Builtin(t *testing.T) {
+	provider, err := Default().QuoteProvider("builtin")
+	assert.NoError(t, err)
+
+	quote, err := provider.GetRandomQuote(context.Background(), quoteapi.NewQuoteConfigBuilder().WithKey(1))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, quote)
+}
+
+func TestImageProvider_WithArgument(t *testing.T) {
+	dir := t.TempDir()
+	provider, err := Default().ImageProvider("dir:" + dir)
+	assert.NoError(t, err)
+	assert.NotNil(t, provider)
+
+	_, err = Default().ImageProvider("dir:" + filepath.Join(dir, "missing"))
+	assert.Error(t, err, "Expected error for a missing directory")
+}
+
+func TestImageProvider_Template(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "template.json")
+	config := `{"url_template": "https://host/img?w={width}&h={height}&g={grayscale}", "filters": {"grayscale": {"on": "1", "off": "0"}}}`
+	assert.NoError(t, os.WriteFile(path, []byte(config), 0o644))
+
+	provider, err := Default().ImageProvider("template:" + path)
+	assert.NoError(t, err)
+	assert.NotNil(t, provider)
+}
+
+func TestImageCatalog_UsesProviderBaseURL(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Write([]byte(`[{"id": "1", "author": "Someone"}]`))
+	}))
+	defer server.Close()
+
+	catalog, ok := ImageCatalog("picsum:"+server.URL, Settings{})
+	assert.True(t, ok)
+	images, err := catalog.ListImages(context.Background(), imageapi.CatalogQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, "/v2/list", requested)
+
+	_, ok = ImageCatalog("generated", Settings{})
+	assert.False(t, ok, "Expected no catalog for a provider other than picsum")
+}
+
+func TestProvider_Unknown(t *testing.T) {
+	_, err := Default().QuoteProvider("missing")
+	assert.ErrorContains(t, err, "unknown quote provider")
+
+	_, err = Default().ImageProvider("missing")
+	assert.ErrorContains(t, err, "unknown image provider")
+}
+
+func TestRegisterImageProvider(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterImageProvider("custom", func(arg string) (imageapi.ImageProvider, error) {
+		if arg == "" {
+			return nil, errors.New("argument required")
+		}
+		return imageapi.NewGeneratedImageProvider(), nil
+	})
+
+	_, err := r.ImageProvider("custom")
+	assert.Error(t, err)
+	provider, err := r.ImageProvider("Custom:x")
+	assert.NoError(t, err)
+	assert.NotNil(t, provider)
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"image"
+	"slices"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/shared"
+)
+
+// PairRequest describes the quote and image to fetch. It is an immutable, validated value:
+// create it with NewPairRequest or a PairRequestBuilder, and share it freely between goroutines.
+type PairRequest struct {
+	quote quoteapi.QuoteConfig
+	image imageapi.ImageConfig
+}
+
+// NewPairRequest validates the quote and image configurations and returns a PairRequest
+// holding copies of them. Empty filter names are ignored.
+func NewPairRequest(quote quoteapi.QuoteConfig, image imageapi.ImageConfig) (PairRequest, error) {
+	if quote.Key < 0 || quote.Key > quoteapi.MaxKeyValue {
+		return PairRequest{}, shared.InvalidInput("quote key must be between 0 and %d, got %d", quoteapi.MaxKeyValue, quote.Key)
+	}
+	if image.Width < 1 || image.Width > imageapi.MaxImageWidth {
+		return PairRequest{}, shared.InvalidInput("image width must be between 1 and %d, got %d", imageapi.MaxImageWidth, image.Width)
+	}
+	if image.Height < 1 || image.Height > imageapi.MaxImageHeight {
+		return PairRequest{}, shared.InvalidInput("image height must be between 1 and %d, got %d", imageapi.MaxImageHeight, image.Height)
+	}
+
+	filters := imageapi.ImageFilters{}
+	for _, filter := range image.Filters {
+		switch filter {
+		case "":
+		case imageapi.ImageFilterGrayscale, imageapi.ImageFilterBlur:
+			if !slices.Contains(filters, filter) {
+				filters = append(filters, filter)
+			}
+		default:
+			return PairRequest{}, shared.InvalidInput("unknown image filter %q", filter)
+		}
+	}
+	image.Filters = slices.Clip(filters)
+	image.Keywords = slices.Clone(image.Keywords)
+	return PairRequest{quote: quote, image: image}, nil
+}
+
+// Validate reports whether the request is valid. Requests created with NewPairRequest always are,
+// while the zero PairRequest is not.
+func (r PairRequest) Validate() error {
+	_, err := NewPairRequest(r.quote, r.image)
+	return err
+}
+
+// Quote returns the quote configuration of the request.
+func (r PairRequest) Quote() quoteapi.QuoteConfig {
+	return r.quote
+}
+
+// Image returns a copy of the image configuration of the request.
+func (r PairRequest) Image() imageapi.ImageConfig {
+	image := r.image
+	image.Filters = slices.Clone(image.Filters)
+	image.Keywords = slices.Clone(image.Keywords)
+	return image
+}
+
+// QuoteConfigBuilder returns a new builder holding the quote configuration, for calling quote providers.
+func (r PairRequest) QuoteConfigBuilder() *quoteapi.QuoteConfigBuilder {
+	return quoteapi.NewQuoteConfigBuilder().WithKey(r.quote.Key)
+}
+
+// ImageConfigBuilder returns a new builder holding the image configuration, for calling image providers.
+// Changing the builder does not affect the request.
+func (r PairRequest) ImageConfigBuilder() *imageapi.ImageConfigBuilder {
+	image := r.Image()
+	return imageapi.NewImageConfigBuilder().
+		WithWidth(image.Width).
+		WithHeight(image.Height).
+		WithFilters(image.Filters).
+		WithSeed(image.Seed).
+		WithImageID(image.ImageID).
+		WithKeywords(image.Keywords)
+}
+
+// PairRequestBuilder provides methods for building a PairRequest.
+type PairRequestBuilder struct {
+	quote *quoteapi.QuoteConfigBuilder
+	image *imageapi.ImageConfigBuilder
+}
+
+// NewPairRequestBuilder creates a new PairRequestBuilder with the default quote and image configurations.
+func NewPairRequestBuilder() *PairRequestBuilder {
+	return &PairRequestBuilder{
+		quote: quoteapi.NewQuoteConfigBuilder(),
+		image: imageapi.NewImageConfigBuilder(),
+	}
+}
+
+// WithQuoteKey sets the quote key and returns the builder instance.
+func (prb *PairRequestBuilder) WithQuoteKey(key int) *PairRequestBuilder {
+	prb.quote.WithKey(key)
+	return prb
+}
+
+// WithWidth sets the image width and returns the builder instance.
+func (prb *PairRequestBuilder) WithWidth(w int) *PairRequestBuilder {
+	prb.image.WithWidth(w)
+	return prb
+}
+
+// WithHeight sets the image height and returns the builder instance.
+func (prb *PairRequestBuilder) WithHeight(h int) *PairRequestBuilder {
+	prb.image.WithHeight(h)
+	return prb
+}
+
+// WithFilters adds image filters and returns the builder instance.
+func (prb *PairRequestBuilder) WithFilters(filters imageapi.ImageFilters) *PairRequestBuilder {
+	prb.image.WithFilters(filters)
+	return prb
+}
+
+// WithSeed sets the image seed and returns the builder instance.
+func (prb *PairRequestBuilder) WithSeed(seed string) *PairRequestBuilder {
+	prb.image.WithSeed(seed)
+	return prb
+}
+
+// WithImageID pins a catalog image and returns the builder instance.
+func (prb *PairRequestBuilder) WithImageID(id string) *PairRequestBuilder {
+	prb.image.WithImageID(id)
+	return prb
+}
+
+// Build validates the configuration and returns the PairRequest.
+func (prb *PairRequestBuilder) Build() (PairRequest, error) {
+	return NewPairRequest(prb.quote.Build(), prb.image.Build())
+}
+
+// PairResult is a quote and the image fetched for it.
+type PairResult struct {
+	Quote  string
+	Author string
+	Image  image.Image
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairRequestBuilder_invalidValues(t *testing.T) {
+
+	_, err := NewPairRequestBuilder().WithWidth(0).Build()
+	assert.Error(t, err, "Expected an error for a zero width")
+
+	_, err = NewPairRequest(quoteapi.NewQuoteConfigBuilder().Build(), imageapi.ImageConfig{Width: 10, Height: imageapi.MaxImageHeight + 1})
+	assert.Error(t, err, "Expected an error for a height above the maximum")
+
+	_, err = NewPairRequestBuilder().WithQuoteKey(-1).Build()
+	assert.Error(t, err, "Expected an error for a negative quote key")
+
+	_, err = NewPairRequestBuilder().WithFilters(imageapi.ImageFilters{"sepia"}).Build()
+	assert.ErrorContains(t, err, `unknown image filter "sepia"`)
+}
+
+func TestPairRequestBuilder_normalizesFilters(t *testing.T) {
+
+	req, err := NewPairRequestBuilder().WithFilters(imageapi.ImageFilters{"", "blur", "grayscale", "blur"}).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, imageapi.ImageFilters{"blur", "grayscale"}, req.Image().Filters)
+}
+
+func TestPairRequest_imageIsCopied(t *testing.T) {
+
+	req, err := NewPairRequestBuilder().WithFilters(imageapi.ImageFilters{"blur"}).Build()
+	assert.NoError(t, err)
+
+	image := req.Image()
+	image.Filters[0] = "grayscale"
+	req.ImageConfigBuilder().WithFilters(imageapi.ImageFilters{"grayscale"})
+
+	assert.Equal(t, imageapi.ImageFilters{"blur"}, req.Image().Filters, "Changing a copy must not change the request")
+}
+
+func TestPairRequestBuilder_reuseAfterBuild(t *testing.T) {
+
+	builder := NewPairRequestBuilder().WithWidth(20).WithFilters(imageapi.ImageFilters{"blur"})
+	first, err := builder.Build()
+	assert.NoError(t, err)
+
+	second, err := builder.WithWidth(30).WithFilters(imageapi.ImageFilters{"grayscale"}).Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 20, first.Image().Width)
+	assert.Equal(t, imageapi.ImageFilters{"blur"}, first.Image().Filters)
+	assert.Equal(t, 30, second.Image().Width)
+	assert.Equal(t, imageapi.ImageFilters{"blur", "grayscale"}, second.Image().Filters)
+}
+
+func TestPairRequest_zeroValueIsInvalid(t *testing.T) {
+
+	assert.Error(t, PairRequest{}.Validate())
+}
@@ -2,17 +2,41 @@ package app
 
 import (
 	"image"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/ramyad/tucows/internal/card"
 )
 
+// Options describes a single quote/image request. Applications derive their defaults from
+// the loaded configuration with NewOptionsFromConfig.
 type Options struct {
 	QuoteCategory int
 	ImageWidth    int
 	ImageHeight   int
 	Filters       []string
+	ImageID       string
+	// Card, when set, renders the quote onto the image as a single quote card.
+	Card *card.CardConfigBuilder
 }
 
 func NewOptions(quoteCategory, imageWidth, imageHeight int, filters []string) *Options {
-	return &Options{quoteCategory, imageWidth, imageHeight, filters}
+	return &Options{
+		QuoteCategory: quoteCategory,
+		ImageWidth:    imageWidth,
+		ImageHeight:   imageHeight,
+		Filters:       filters,
+	}
+}
+
+// PairRequest converts the options into a validated request for the API.
+func (o Options) PairRequest() (api.PairRequest, error) {
+	return api.NewPairRequestBuilder().
+		WithQuoteKey(o.QuoteCategory).
+		WithWidth(o.ImageWidth).
+		WithHeight(o.ImageHeight).
+		WithFilters(o.Filters).
+		WithImageID(o.ImageID).
+		Build()
 }
 
 type App interface {
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/ramyad/tucows/internal/api/breaker"
+	"github.com/ramyad/tucows/internal/api/facade"
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/registry"
+	"github.com/ramyad/tucows/internal/config"
+)
+
+// NewOptionsFromConfig returns the request options configured by cfg.
+func NewOptionsFromConfig(cfg *config.Config) (Options, error) {
+	cardCnfgBldr, err := cfg.CardConfigBuilder()
+	if err != nil {
+		return Options{}, err
+	}
+	return Options{
+		QuoteCategory: cfg.Quote.Category,
+		ImageWidth:    cfg.Image.Width,
+		ImageHeight:   cfg.Image.Height,
+		Filters:       slices.Clone(cfg.Image.Filters),
+		ImageID:       cfg.Image.ID,
+		Card:          cardCnfgBldr,
+	}, nil
+}
+
+// NewCatalog returns the catalog of the image provider configured by cfg, or nil when the
+// provider has no catalog.
+func NewCatalog(cfg *config.Config, logger *slog.Logger) imageapi.ImageCatalog {
+	settings := cfg.RegistrySettings()
+	settings.Logger = logger
+	catalog, ok := registry.ImageCatalog(cfg.Image.Provider, settings)
+	if !ok {
+		return nil
+	}
+	return catalog
+}
+
+// NewAPI creates the API configured by cfg, logging to logger. Unless they are disabled, both
+// providers are wrapped in circuit breakers, which are returned so their state can be reported.
+func NewAPI(cfg *config.Config, logger *slog.Logger) (api.API, []*breaker.Breaker, error) {
+	settings := cfg.RegistrySettings()
+	settings.Logger = logger
+	providers := registry.DefaultWithSettings(settings)
+	quoteProvider, err := providers.QuoteProvider(cfg.Quote.Provider)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create quote provider: %w", err)
+	}
+	imageProvider, err := providers.ImageProvider(cfg.Image.Provider)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create image provider: %w", err)
+	}
+
+	var breakers []*breaker.Breaker
+	if cfg.Resilience.BreakerThreshold > 0 {
+		quoteBreaker := breaker.NewBreakerBuilder("quote:" + cfg.Quote.Provider).WithLogger(logger).WithFailureThreshold(cfg.Resilience.BreakerThreshold).WithCooldown(cfg.Resilience.BreakerCooldown).Build()
+		imageBreaker := breaker.NewBreakerBuilder("image:" + cfg.Image.Provider).WithLogger(logger).WithFailureThreshold(cfg.Resilience.BreakerThreshold).WithCooldown(cfg.Resilience.BreakerCooldown).Build()
+		quoteProvider = breaker.WrapQuoteProvider(quoteProvider, quoteBreaker)
+		imageProvider = breaker.WrapImageProvider(imageProvider, imageBreaker)
+		breakers = append(breakers, quoteBreaker, imageBreaker)
+	}
+
+	facadeOptions := []facade.Option{
+		facade.WithQuoteProvider(quoteProvider),
+		facade.WithImageProvider(imageProvider),
+		facade.WithLogger(logger),
+		facade.WithQuoteHedgeDelay(cfg.Resilience.QuoteHedgeDelay),
+		facade.WithImageHedgeDelay(cfg.Resilience.ImageHedgeDelay),
+		facade.WithPrefetchPool(cfg.Pairing.Prefetch),
+		facade.WithQuoteKeywords(cfg.Pairing.Keywords),
+	}
+	switch cfg.Pairing.Mood {
+	case config.MoodFilters:
+		facadeOptions = append(facadeOptions, facade.WithMoodFilters())
+	case config.MoodPalette:
+		facadeOptions = append(facadeOptions, facade.WithMoodPalette(cfg.Pairing.MoodCandidates))
+	}
+	if cfg.Resilience.Degraded {
+		facadeOptions = append(facadeOptions, facade.WithDegradedMode())
+	}
+	return facade.NewAPIFacade(facadeOptions...), breakers, nil
+}
@@ -0,0 +1,147 @@
+package terminal
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/ramyad/tucows/internal/config"
+)
+
+const (
+	// edgeThreshold is the smallest Sobel gradient magnitude, for brightness in [0, 1],
+	// drawn as an edge by ASCIIRenderer.
+	edgeThreshold = 1.0
+	// brailleBlank is the Braille pattern without raised dots.
+	brailleBlank = 0x2800
+)
+
+// brailleDots are the bits of the Braille dots, indexed by their row and column in the cell.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// ASCIIRenderer draws the image as ASCII art without escape sequences, one pixel per character.
+type ASCIIRenderer struct {
+	// Ramp lists the characters from dark to bright; config.DefaultRamp is used when it has
+	// fewer than two characters.
+	Ramp string
+	// Edges draws strong edges with the line characters - | / and \ instead of the ramp.
+	Edges bool
+	// Dither spreads the error of mapping brightness to the ramp.
+	Dither Dither
+}
+
+// Render maps the brightness of every pixel to the character of the ramp closest to it.
+func (r ASCIIRenderer) Render(out io.Writer, img image.Image, columns, rows int) error {
+	gray := grayLevels(scaleImage(img, columns, rows))
+	ramp := []rune(r.Ramp)
+	if len(ramp) < 2 {
+		ramp = []rune(config.DefaultRamp)
+	}
+	levels := ditherLevels(gray, columns, rows, len(ramp), r.Dither)
+
+	lines := make([]string, rows)
+	var line strings.Builder
+	for y := range lines {
+		line.Reset()
+		for x := 0; x < columns; x++ {
+			if r.Edges {
+				if edge, ok := edgeAt(gray, columns, rows, x, y); ok {
+					line.WriteRune(edge)
+					continue
+				}
+			}
+			line.WriteRune(ramp[levels[y*columns+x]])
+		}
+		lines[y] = line.String()
+	}
+	return writeLines(out, lines)
+}
+
+// BrailleRenderer draws the image as Unicode Braille patterns without escape sequences,
+// raising the dots of bright pixels.
+type BrailleRenderer struct {
+	// Dither spreads the error of reducing pixels to raised and flat dots.
+	Dither Dither
+}
+
+// Render draws 2x4 pixels per character.
+func (r BrailleRenderer) Render(out io.Writer, img image.Image, columns, rows int) error {
+	pixelsWide, pixelsHigh := 2*columns, 4*rows
+	dots := ditherLevels(grayLevels(scaleImage(img, pixelsWide, pixelsHigh)), pixelsWide, pixelsHigh, 2, r.Dither)
+
+	lines := make([]string, rows)
+	var line strings.Builder
+	for row := range lines {
+		line.Reset()
+		for column := 0; column < columns; column++ {
+			pattern := rune(brailleBlank)
+			for dy, bits := range brailleDots {
+				for dx, bit := range bits {
+					if dots[(4*row+dy)*pixelsWide+2*column+dx] == 1 {
+						pattern |= bit
+					}
+				}
+			}
+			line.WriteRune(pattern)
+		}
+		lines[row] = line.String()
+	}
+	return writeLines(out, lines)
+}
+
+// writeLines writes every line followed by a newline.
+func writeLines(out io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return fmt.Errorf("failed to display image: %w", err)
+		}
+	}
+	return nil
+}
+
+// grayLevels returns the brightness of every pixel of img in [0, 1], in row-major order.
+func grayLevels(img *image.RGBA) []float64 {
+	bounds := img.Bounds()
+	gray := make([]float64, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray = append(gray, float64(color.GrayModel.Convert(img.RGBAAt(x, y)).(color.Gray).Y)/255)
+		}
+	}
+	return gray
+}
+
+// edgeAt returns the line character along the edge at x, y, or false when the Sobel
+// gradient there is weaker than edgeThreshold.
+func edgeAt(gray []float64, width, height, x, y int) (rune, bool) {
+	at := func(dx, dy int) float64 {
+		px, py := min(max(x+dx, 0), width-1), min(max(y+dy, 0), height-1)
+		return gray[py*width+px]
+	}
+	gx := at(1, -1) + 2*at(1, 0) + at(1, 1) - at(-1, -1) - 2*at(-1, 0) - at(-1, 1)
+	gy := at(-1, 1) + 2*at(0, 1) + at(1, 1) - at(-1, -1) - 2*at(0, -1) - at(1, -1)
+	if math.Hypot(gx, gy) < edgeThreshold {
+		return 0, false
+	}
+
+	// The edge runs across the gradient; y grows downwards.
+	angle := math.Mod(math.Atan2(gy, gx)*180/math.Pi+180, 180)
+	switch {
+	case angle < 22.5 || angle >= 157.5:
+		return '|', true
+	case angle < 67.5:
+		return '/', true
+	case angle < 112.5:
+		return '-', true
+	default:
+		return '\\', true
+	}
+}
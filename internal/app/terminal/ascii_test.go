@@ -0,0 +1,86 @@
+package terminal
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// uniform returns a width x height image filled with c.
+func uniform(width, height int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestASCIIRenderer(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 1))
+	for x, level := range []uint8{0, 100, 180, 255} {
+		img.Set(x, 0, color.Gray{Y: level})
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, ASCIIRenderer{Ramp: " .:-=+*#%@"}.Render(&out, img, 4, 1))
+	assert.Equal(t, " =*@\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, ASCIIRenderer{Ramp: "@. "}.Render(&out, img, 4, 1))
+	assert.Equal(t, "@.. \n", out.String())
+
+	for _, ramp := range []string{"", "#"} {
+		out.Reset()
+		assert.NoError(t, ASCIIRenderer{Ramp: ramp}.Render(&out, img, 4, 1))
+		assert.Equal(t, " =*@\n", out.String(), "Expected the default ramp for %q", ramp)
+	}
+}
+
+func TestASCIIRenderer_Edges(t *testing.T) {
+	// A bright left half and a dark right half meet at a vertical edge.
+	img := uniform(8, 4, color.Black)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, ASCIIRenderer{Ramp: " #", Edges: true}.Render(&out, img, 8, 4))
+	assert.Equal(t, "###||   \n###||   \n###||   \n###||   \n", out.String())
+
+	// Rotated, the edge between a bright top and a dark bottom is horizontal.
+	img = uniform(8, 4, color.Black)
+	for x := 0; x < 8; x++ {
+		img.Set(x, 0, color.White)
+		img.Set(x, 1, color.White)
+	}
+	out.Reset()
+	assert.NoError(t, ASCIIRenderer{Ramp: " #", Edges: true}.Render(&out, img, 8, 4))
+	assert.Equal(t, "########\n--------\n--------\n        \n", out.String())
+}
+
+func TestBrailleRenderer(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, BrailleRenderer{}.Render(&out, uniform(4, 8, color.White), 2, 2))
+	assert.Equal(t, "⣿⣿\n⣿⣿\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, BrailleRenderer{}.Render(&out, uniform(4, 8, color.Black), 2, 2))
+	assert.Equal(t, "⠀⠀\n⠀⠀\n", out.String())
+
+	// Bright left columns raise the left dots only.
+	img := uniform(4, 4, color.Black)
+	for y := 0; y < 4; y++ {
+		img.Set(0, y, color.White)
+		img.Set(2, y, color.White)
+	}
+	out.Reset()
+	assert.NoError(t, BrailleRenderer{}.Render(&out, img, 2, 1))
+	assert.Equal(t, "⡇⡇\n", out.String())
+}
@@ -0,0 +1,329 @@
+package terminal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ramyad/tucows/internal/config"
+)
+
+// ColorMode is the set of colors the image is rendered with.
+type ColorMode int
+
+const (
+	// ColorNone renders the image as plain text without any escape sequences.
+	ColorNone ColorMode = iota
+	// Color16 quantizes the image to the 16 standard ANSI colors.
+	Color16
+	// Color256 quantizes the image to the xterm 256-color palette.
+	Color256
+	// ColorTrueColor renders the image with 24-bit colors.
+	ColorTrueColor
+)
+
+// String returns the -color value selecting the mode.
+func (m ColorMode) String() string {
+	switch m {
+	case Color16:
+		return config.Color16
+	case Color256:
+		return config.Color256
+	case ColorTrueColor:
+		return config.ColorTrueColor
+	default:
+		return config.ColorNone
+	}
+}
+
+// resolveColorMode returns the mode selected by a terminal.color value, detecting it
+// with DetectColorMode for config.ColorAuto.
+func resolveColorMode(value string, getenv func(string) string, out io.Writer) ColorMode {
+	switch value {
+	case config.ColorTrueColor:
+		return ColorTrueColor
+	case config.Color256:
+		return Color256
+	case config.Color16:
+		return Color16
+	case config.ColorNone:
+		return ColorNone
+	default:
+		return DetectColorMode(getenv, isTerminal(out))
+	}
+}
+
+// DetectColorMode returns the color mode supported by the terminal described by the
+// environment. Output that is not a terminal, NO_COLOR and TERM=dumb disable colors.
+// Otherwise COLORTERM=truecolor or 24bit selects 24-bit colors, and the TERM name or its
+// terminfo entry decides between the 256-color and 16-color palettes.
+func DetectColorMode(getenv func(string) string, tty bool) ColorMode {
+	term := getenv("TERM")
+	if !tty || getenv("NO_COLOR") != "" || term == "" || term == "dumb" {
+		return ColorNone
+	}
+	if colorTerm := getenv("COLORTERM"); colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ColorTrueColor
+	}
+	if strings.HasSuffix(term, "-direct") || strings.HasSuffix(term, "-truecolor") {
+		return ColorTrueColor
+	}
+	if strings.Contains(term, "256color") {
+		return Color256
+	}
+
+	colors, ok := terminfoColors(term, terminfoDirs(getenv))
+	switch {
+	case !ok:
+		return Color16
+	case colors >= 1<<24:
+		return ColorTrueColor
+	case colors >= 256:
+		return Color256
+	case colors >= 8:
+		return Color16
+	default:
+		return ColorNone
+	}
+}
+
+// isTerminal reports whether out is a character device, such as a terminal.
+func isTerminal(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminfoDirs returns the directories searched for terminfo entries, in the order ncurses searches them.
+func terminfoDirs(getenv func(string) string) []string {
+	var dirs []string
+	if dir := getenv("TERMINFO"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if home := getenv("HOME"); home != "" {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+	for _, dir := range filepath.SplitList(getenv("TERMINFO_DIRS")) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return append(dirs, "/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo")
+}
+
+const (
+	// terminfoMagic and terminfoMagic32 identify compiled terminfo entries with 16-bit and 32-bit numbers.
+	terminfoMagic   = 0o432
+	terminfoMagic32 = 0o1036
+	// terminfoMaxColors is the index of the max_colors number.
+	terminfoMaxColors = 13
+)
+
+// terminfoColors returns the max_colors capability of the compiled terminfo entry for term.
+// It reports false when there is no readable entry, and 0 colors when the entry has none.
+func terminfoColors(term string, dirs []string) (int, bool) {
+	for _, dir := range dirs {
+		// Entries are stored under their first letter, or its hex code on case-insensitive file systems.
+		for _, sub := range []string{term[:1], fmt.Sprintf("%x", term[0])} {
+			data, err := os.ReadFile(filepath.Join(dir, sub, term))
+			if err == nil {
+				return parseTerminfoColors(data)
+			}
+		}
+	}
+	return 0, false
+}
+
+// parseTerminfoColors reads the max_colors number of a compiled terminfo entry.
+func parseTerminfoColors(data []byte) (int, bool) {
+	if len(data) < 12 {
+		return 0, false
+	}
+	header := func(i int) int { return int(int16(binary.LittleEndian.Uint16(data[2*i:]))) }
+	numberSize := 2
+	switch header(0) {
+	case terminfoMagic:
+	case terminfoMagic32:
+		numberSize = 4
+	default:
+		return 0, false
+	}
+	namesSize, boolCount, numberCount := header(1), header(2), header(3)
+	if numberCount <= terminfoMaxColors {
+		return 0, true
+	}
+
+	offset := 12 + namesSize + boolCount
+	offset += offset % 2
+	offset += terminfoMaxColors * numberSize
+	if namesSize < 0 || boolCount < 0 || offset+numberSize > len(data) {
+		return 0, false
+	}
+	var colors int
+	if numberSize == 4 {
+		colors = int(int32(binary.LittleEndian.Uint32(data[offset:])))
+	} else {
+		colors = int(int16(binary.LittleEndian.Uint16(data[offset:])))
+	}
+	return max(colors, 0), true
+}
+
+// ansi16 is the xterm palette of the 16 standard ANSI colors.
+var ansi16 = [16]color.RGBA{
+	{0, 0, 0, 255}, {205, 0, 0, 255}, {0, 205, 0, 255}, {205, 205, 0, 255},
+	{0, 0, 238, 255}, {205, 0, 205, 255}, {0, 205, 205, 255}, {229, 229, 229, 255},
+	{127, 127, 127, 255}, {255, 0, 0, 255}, {0, 255, 0, 255}, {255, 255, 0, 255},
+	{92, 92, 255, 255}, {255, 0, 255, 255}, {0, 255, 255, 255}, {255, 255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube of the xterm 256-color palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// nearestANSI16 returns the index of the standard ANSI color nearest to c.
+func nearestANSI16(c color.RGBA) int {
+	best, bestDistance := 0, -1
+	for i, candidate := range ansi16 {
+		if d := colorDistance(c, candidate); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return best
+}
+
+// nearestXterm256 returns the index of the xterm 256-color palette entry nearest to c,
+// choosing between the nearest color cube entry and the nearest gray. The first 16 entries
+// are skipped, as terminals commonly change them with their theme.
+func nearestXterm256(c color.RGBA) int {
+	r, g, b := nearestCubeLevel(c.R), nearestCubeLevel(c.G), nearestCubeLevel(c.B)
+	cube := color.RGBA{uint8(cubeLevels[r]), uint8(cubeLevels[g]), uint8(cubeLevels[b]), 255}
+
+	average := (int(c.R) + int(c.G) + int(c.B)) / 3
+	grayIndex := min(max((average-3)/10, 0), 23)
+	grayLevel := uint8(8 + 10*grayIndex)
+	gray := color.RGBA{grayLevel, grayLevel, grayLevel, 255}
+
+	if colorDistance(c, gray) < colorDistance(c, cube) {
+		return 232 + grayIndex
+	}
+	return 16 + 36*r + 6*g + b
+}
+
+// xterm256Color returns the color of an entry of the xterm 256-color palette.
+func xterm256Color(i int) color.RGBA {
+	switch {
+	case i < 16:
+		return ansi16[i]
+	case i >= 232:
+		level := uint8(8 + 10*(i-232))
+		return color.RGBA{level, level, level, 255}
+	default:
+		i -= 16
+		return color.RGBA{uint8(cubeLevels[i/36]), uint8(cubeLevels[i/6%6]), uint8(cubeLevels[i%6]), 255}
+	}
+}
+
+// nearestCubeLevel returns the index of the color cube level nearest to v.
+func nearestCubeLevel(v uint8) int {
+	best := 0
+	for i, level := range cubeLevels {
+		if abs(int(v)-level) < abs(int(v)-cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// colorDistance is the squared euclidean distance between two colors.
+func colorDistance(a, b color.RGBA) int {
+	dr, dg, db := int(a.R)-int(b.R), int(a.G)-int(b.G), int(a.B)-int(b.B)
+	return dr*dr + dg*dg + db*db
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// background returns the escape sequence setting the background color to c,
+// or an empty string for ColorNone.
+func (m ColorMode) background(c color.RGBA) string {
+	switch m {
+	case ColorTrueColor:
+		return fmt.Sprintf("\x1b[48;2;%d;%d;%dm", c.R, c.G, c.B)
+	case Color256:
+		return fmt.Sprintf("\x1b[48;5;%dm", nearestXterm256(c))
+	case Color16:
+		i := nearestANSI16(c)
+		if i >= 8 {
+			return fmt.Sprintf("\x1b[%dm", 100+i-8)
+		}
+		return fmt.Sprintf("\x1b[%dm", 40+i)
+	default:
+		return ""
+	}
+}
+
+// foreground returns the escape sequence setting the foreground color to c,
+// or an empty string for ColorNone.
+func (m ColorMode) foreground(c color.RGBA) string {
+	switch m {
+	case ColorTrueColor:
+		return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)
+	case Color256:
+		return fmt.Sprintf("\x1b[38;5;%dm", nearestXterm256(c))
+	case Color16:
+		i := nearestANSI16(c)
+		if i >= 8 {
+			return fmt.Sprintf("\x1b[%dm", 90+i-8)
+		}
+		return fmt.Sprintf("\x1b[%dm", 30+i)
+	default:
+		return ""
+	}
+}
+
+// palette returns a function returning the nearest color of the palette of m, and the typical
+// distance between the channel values of the palette in [0, 1]. 24-bit colors and ColorNone
+// have no palette and return nil.
+func (m ColorMode) palette() (func(color.RGBA) color.RGBA, float64) {
+	switch m {
+	case Color256:
+		return func(c color.RGBA) color.RGBA { return xterm256Color(nearestXterm256(c)) }, 1.0 / 5
+	case Color16:
+		return func(c color.RGBA) color.RGBA { return ansi16[nearestANSI16(c)] }, 1.0 / 2
+	default:
+		return nil, 0
+	}
+}
+
+// nearestGray returns a function returning the nearest of levels evenly spaced grays.
+func nearestGray(levels int) func(color.RGBA) color.RGBA {
+	steps := float64(levels - 1)
+	return func(c color.RGBA) color.RGBA {
+		gray := float64(color.GrayModel.Convert(c).(color.Gray).Y) / 255
+		v := uint8(math.Round(math.Round(gray*steps) / steps * 255))
+		return color.RGBA{v, v, v, 255}
+	}
+}
+
+// resetColor restores the default colors.
+const resetColor = "\x1b[0m"
+
+// shadeRamp renders brightness without colors, from dark to bright.
+const shadeRamp = config.DefaultRamp
+
+// shade returns the character of shadeRamp matching the brightness of c.
+func shade(c color.RGBA) byte {
+	gray := color.GrayModel.Convert(c).(color.Gray).Y
+	return shadeRamp[int(gray)*len(shadeRamp)/256]
+}
@@ -0,0 +1,103 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// terminfoEntry compiles a minimal terminfo entry with the given max_colors number.
+func terminfoEntry(colors int16) []byte {
+	var data bytes.Buffer
+	names := "test|test terminal\x00"
+	for _, n := range []int16{terminfoMagic, int16(len(names)), 1, terminfoMaxColors + 1, 0, 0} {
+		_ = binary.Write(&data, binary.LittleEndian, n)
+	}
+	data.WriteString(names)
+	data.WriteByte(1)
+	if data.Len()%2 != 0 {
+		data.WriteByte(0)
+	}
+	for i := 0; i < terminfoMaxColors; i++ {
+		_ = binary.Write(&data, binary.LittleEndian, int16(-1))
+	}
+	_ = binary.Write(&data, binary.LittleEndian, colors)
+	return data.Bytes()
+}
+
+func TestDetectColorMode(t *testing.T) {
+	terminfo := t.TempDir()
+	for term, colors := range map[string]int16{"mono": -1, "basic": 8, "rich": 256} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(terminfo, term[:1]), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(terminfo, term[:1], term), terminfoEntry(colors), 0o644))
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		tty  bool
+		want ColorMode
+	}{
+		{"not a terminal", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, false, ColorNone},
+		{"NO_COLOR", map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}, true, ColorNone},
+		{"dumb terminal", map[string]string{"TERM": "dumb"}, true, ColorNone},
+		{"COLORTERM", map[string]string{"TERM": "xterm", "COLORTERM": "24bit"}, true, ColorTrueColor},
+		{"direct TERM", map[string]string{"TERM": "xterm-direct"}, true, ColorTrueColor},
+		{"256color TERM", map[string]string{"TERM": "screen-256color"}, true, Color256},
+		{"terminfo 256 colors", map[string]string{"TERM": "rich", "TERMINFO": terminfo}, true, Color256},
+		{"terminfo 8 colors", map[string]string{"TERM": "basic", "TERMINFO": terminfo}, true, Color16},
+		{"terminfo without colors", map[string]string{"TERM": "mono", "TERMINFO": terminfo}, true, ColorNone},
+		{"unknown TERM", map[string]string{"TERM": "unknown-terminal", "TERMINFO": terminfo}, true, Color16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			assert.Equal(t, tt.want, DetectColorMode(getenv, tt.tty))
+		})
+	}
+}
+
+func TestResolveColorMode(t *testing.T) {
+	getenv := func(string) string { return "" }
+	assert.Equal(t, Color256, resolveColorMode("256", getenv, &bytes.Buffer{}))
+	assert.Equal(t, ColorNone, resolveColorMode("auto", getenv, &bytes.Buffer{}))
+}
+
+func TestNearestXterm256(t *testing.T) {
+	assert.Equal(t, 16, nearestXterm256(color.RGBA{0, 0, 0, 255}))
+	assert.Equal(t, 231, nearestXterm256(color.RGBA{255, 255, 255, 255}))
+	assert.Equal(t, 196, nearestXterm256(color.RGBA{250, 10, 5, 255}))
+	assert.Equal(t, 244, nearestXterm256(color.RGBA{128, 128, 128, 255}))
+}
+
+func TestNearestANSI16(t *testing.T) {
+	assert.Equal(t, 0, nearestANSI16(color.RGBA{10, 10, 10, 255}))
+	assert.Equal(t, 1, nearestANSI16(color.RGBA{200, 20, 20, 255}))
+	assert.Equal(t, 12, nearestANSI16(color.RGBA{90, 90, 250, 255}))
+	assert.Equal(t, 15, nearestANSI16(color.RGBA{250, 250, 250, 255}))
+}
+
+func TestTextRenderer_ColorModes(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+
+	tests := map[ColorMode]string{
+		ColorTrueColor: "\x1b[48;2;255;0;0m  \x1b[0m\n",
+		Color256:       "\x1b[48;5;196m  \x1b[0m\n",
+		Color16:        "\x1b[101m  \x1b[0m\n",
+		ColorNone:      "::\n",
+	}
+	for mode, want := range tests {
+		t.Run(mode.String(), func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, NewRenderer(RenderBlocks, mode, DitherNone).Render(&out, img, 2, 1))
+			assert.Equal(t, want, out.String())
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package terminal
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/ramyad/tucows/internal/config"
+)
+
+// Dither selects how the error of reducing colors to a palette is spread over the image.
+type Dither int
+
+const (
+	// DitherNone replaces every pixel by its nearest palette color.
+	DitherNone Dither = iota
+	// DitherFloydSteinberg diffuses the whole error of a pixel to its four unvisited neighbors.
+	DitherFloydSteinberg
+	// DitherAtkinson diffuses three quarters of the error to six neighbors, for more contrast.
+	DitherAtkinson
+	// DitherBayer offsets every pixel by a 4x4 Bayer threshold matrix before quantizing it.
+	DitherBayer
+)
+
+// String returns the -dither value selecting the method.
+func (d Dither) String() string {
+	switch d {
+	case DitherFloydSteinberg:
+		return config.DitherFloydSteinberg
+	case DitherAtkinson:
+		return config.DitherAtkinson
+	case DitherBayer:
+		return config.DitherBayer
+	default:
+		return config.DitherNone
+	}
+}
+
+// parseDither returns the method selected by a terminal.dither value.
+func parseDither(value string) Dither {
+	switch value {
+	case config.DitherFloydSteinberg:
+		return DitherFloydSteinberg
+	case config.DitherAtkinson:
+		return DitherAtkinson
+	case config.DitherBayer:
+		return DitherBayer
+	default:
+		return DitherNone
+	}
+}
+
+// diffusion is an error diffusion kernel: the share of the error given to the neighbor at dx, dy.
+type diffusion []struct {
+	dx, dy int
+	weight float64
+}
+
+var (
+	floydSteinbergKernel = diffusion{{1, 0, 7.0 / 16}, {-1, 1, 3.0 / 16}, {0, 1, 5.0 / 16}, {1, 1, 1.0 / 16}}
+	atkinsonKernel       = diffusion{{1, 0, 1.0 / 8}, {2, 0, 1.0 / 8}, {-1, 1, 1.0 / 8}, {0, 1, 1.0 / 8}, {1, 1, 1.0 / 8}, {0, 2, 1.0 / 8}}
+	bayerMatrix          = [4][4]float64{{0, 8, 2, 10}, {12, 4, 14, 6}, {3, 11, 1, 9}, {15, 7, 13, 5}}
+)
+
+// dither quantizes a width x height image of channels values per pixel, each in [0, 1], in place.
+// quantize replaces the values of a pixel by those of its nearest palette color; spread is the
+// typical distance between palette values, which scales the Bayer thresholds. The pixels are
+// visited in row-major order, so the result is deterministic.
+func dither(values []float64, width, height, channels int, method Dither, spread float64, quantize func(pixel []float64)) {
+	var kernel diffusion
+	switch method {
+	case DitherFloydSteinberg:
+		kernel = floydSteinbergKernel
+	case DitherAtkinson:
+		kernel = atkinsonKernel
+	}
+
+	errs := make([]float64, channels)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixel := values[(y*width+x)*channels : (y*width+x+1)*channels]
+			copy(errs, pixel)
+			if method == DitherBayer {
+				offset := (0.5 - (bayerMatrix[y%4][x%4]+0.5)/16) * spread
+				for i := range pixel {
+					pixel[i] += offset
+				}
+			}
+			quantize(pixel)
+			if kernel == nil {
+				continue
+			}
+
+			for i := range errs {
+				errs[i] -= pixel[i]
+			}
+			for _, k := range kernel {
+				nx, ny := x+k.dx, y+k.dy
+				if nx < 0 || nx >= width || ny >= height {
+					continue
+				}
+				neighbor := values[(ny*width+nx)*channels:]
+				for i, err := range errs {
+					neighbor[i] += err * k.weight
+				}
+			}
+		}
+	}
+}
+
+// ditherLevels quantizes gray values in [0, 1] of a width x height image to levels evenly spaced
+// levels, from 0 for black to levels-1 for white.
+func ditherLevels(gray []float64, width, height, levels int, method Dither) []int {
+	values := append([]float64(nil), gray...)
+	steps := float64(levels - 1)
+	dither(values, width, height, 1, method, 1/steps, func(pixel []float64) {
+		pixel[0] = math.Round(min(max(pixel[0], 0), 1)*steps) / steps
+	})
+
+	indexes := make([]int, len(values))
+	for i, v := range values {
+		indexes[i] = int(math.Round(v * steps))
+	}
+	return indexes
+}
+
+// ditherImage replaces every pixel of img by a color of a palette. nearest returns the palette
+// color closest to a color, and spread is the typical distance between palette channel values in [0, 1].
+func ditherImage(img *image.RGBA, method Dither, spread float64, nearest func(color.RGBA) color.RGBA) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	values := make([]float64, 0, 3*width*height)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			values = append(values, float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+		}
+	}
+
+	channel := func(v float64) uint8 {
+		return uint8(math.Round(min(max(v, 0), 1) * 255))
+	}
+	dither(values, width, height, 3, method, spread, func(pixel []float64) {
+		c := nearest(color.RGBA{channel(pixel[0]), channel(pixel[1]), channel(pixel[2]), 255})
+		pixel[0], pixel[1], pixel[2] = float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	})
+
+	for i := 0; i < width*height; i++ {
+		img.SetRGBA(bounds.Min.X+i%width, bounds.Min.Y+i/width, color.RGBA{
+			channel(values[3*i]), channel(values[3*i+1]), channel(values[3*i+2]), 255,
+		})
+	}
+}
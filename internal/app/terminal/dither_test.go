@@ -0,0 +1,100 @@
+package terminal
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mean returns the average of levels, scaled from levels-1 steps to [0, 1].
+func mean(levels []int, steps int) float64 {
+	sum := 0
+	for _, level := range levels {
+		sum += level
+	}
+	return float64(sum) / float64(len(levels)*steps)
+}
+
+func TestDitherLevels_PreservesBrightness(t *testing.T) {
+	gray := make([]float64, 16*16)
+	for i := range gray {
+		gray[i] = 0.25
+	}
+	// Atkinson drops a quarter of the error, which darkens dark grays.
+	tests := map[Dither]float64{DitherFloydSteinberg: 0.03, DitherAtkinson: 0.1, DitherBayer: 0.03}
+	for method, delta := range tests {
+		t.Run(method.String(), func(t *testing.T) {
+			assert.InDelta(t, 0.25, mean(ditherLevels(gray, 16, 16, 2, method), 1), delta)
+			assert.InDelta(t, 0.25, mean(ditherLevels(gray, 16, 16, 3, method), 2), delta)
+		})
+	}
+}
+
+func TestDitherLevels_None(t *testing.T) {
+	assert.Equal(t, []int{0, 0, 1, 1, 2}, ditherLevels([]float64{0, 0.2, 0.3, 0.7, 0.9}, 5, 1, 3, DitherNone))
+}
+
+func TestDitherLevels_Bayer(t *testing.T) {
+	gray := make([]float64, 4*4)
+	for i := range gray {
+		gray[i] = 0.5
+	}
+	// Half gray raises the pixels with the lower half of the thresholds.
+	assert.Equal(t, []int{
+		1, 0, 1, 0,
+		0, 1, 0, 1,
+		1, 0, 1, 0,
+		0, 1, 0, 1,
+	}, ditherLevels(gray, 4, 4, 2, DitherBayer))
+}
+
+func TestDitherLevels_Deterministic(t *testing.T) {
+	gray := make([]float64, 8*8)
+	for i := range gray {
+		gray[i] = float64(i) / float64(len(gray))
+	}
+	for _, method := range []Dither{DitherFloydSteinberg, DitherAtkinson, DitherBayer} {
+		assert.Equal(t, ditherLevels(gray, 8, 8, 4, method), ditherLevels(gray, 8, 8, 4, method), method.String())
+	}
+}
+
+func TestDitherImage_Palette(t *testing.T) {
+	nearest, spread := Color16.palette()
+	for _, method := range []Dither{DitherFloydSteinberg, DitherAtkinson, DitherBayer} {
+		t.Run(method.String(), func(t *testing.T) {
+			img := uniform(8, 8, color.RGBA{128, 64, 32, 255})
+			ditherImage(img, method, spread, nearest)
+			for y := 0; y < 8; y++ {
+				for x := 0; x < 8; x++ {
+					c := img.RGBAAt(x, y)
+					assert.Equal(t, nearest(c), c)
+				}
+			}
+		})
+	}
+}
+
+func TestTextRenderer_Dither(t *testing.T) {
+	// Without dithering, mid-gray becomes its nearest gray; dithered, it mixes black and white.
+	img := uniform(8, 8, color.Gray{Y: 128})
+	levels := func(dither Dither) map[uint8]bool {
+		scaled := image.NewRGBA(img.Bounds())
+		copy(scaled.Pix, img.Pix)
+		textRenderer{cell: &halfBlocks, colorMode: ColorNone, dither: dither}.reduce(scaled, 2)
+		seen := map[uint8]bool{}
+		for i := 0; i < len(scaled.Pix); i += 4 {
+			seen[scaled.Pix[i]] = true
+		}
+		return seen
+	}
+	assert.Equal(t, map[uint8]bool{128: true}, levels(DitherNone))
+	assert.Equal(t, map[uint8]bool{0: true, 255: true}, levels(DitherFloydSteinberg))
+}
+
+func TestParseDither(t *testing.T) {
+	for _, method := range []Dither{DitherNone, DitherFloydSteinberg, DitherAtkinson, DitherBayer} {
+		assert.Equal(t, method, parseDither(method.String()))
+	}
+}
@@ -0,0 +1,241 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+)
+
+const (
+	// defaultCellWidth and defaultCellHeight are the assumed size of a character cell
+	// in pixels when the terminal does not report it.
+	defaultCellWidth  = 8
+	defaultCellHeight = 16
+	// kittyChunkSize is the largest base64 payload of a single Kitty graphics command.
+	kittyChunkSize = 4096
+	// graphicsQuery asks the terminal whether it supports the Kitty graphics protocol,
+	// followed by a primary device attributes request that every terminal answers.
+	graphicsQuery = "\x1b_Gi=31,s=1,v=1,a=q,t=d,f=24;AAAA\x1b\\\x1b[c"
+	// kittyQueryOK is the answer of a terminal supporting the Kitty graphics protocol.
+	kittyQueryOK = "\x1b_Gi=31;OK"
+	// sixelAttribute is the device attribute reported by terminals supporting Sixel graphics.
+	sixelAttribute = "4"
+)
+
+// DetectGraphics returns the graphics protocol supported by the terminal, or false when it
+// supports none. The Kitty and iTerm2 protocols are recognized by the environment variables
+// their terminals set; otherwise query asks the terminal with graphicsQuery. Terminal
+// multiplexers such as tmux and screen are assumed not to pass images through.
+func DetectGraphics(getenv func(string) string, query func() (string, error)) (RenderMode, bool) {
+	term := getenv("TERM")
+	if getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux") {
+		return RenderBlocks, false
+	}
+	switch {
+	case term == "xterm-kitty" || getenv("KITTY_WINDOW_ID") != "" || getenv("TERM_PROGRAM") == "ghostty":
+		return RenderKitty, true
+	case getenv("TERM_PROGRAM") == "iTerm.app" || getenv("LC_TERMINAL") == "iTerm2" || getenv("TERM_PROGRAM") == "WezTerm":
+		return RenderITerm2, true
+	}
+
+	response, err := query()
+	if err != nil {
+		return RenderBlocks, false
+	}
+	kitty, sixel := parseGraphicsResponse(response)
+	switch {
+	case kitty:
+		return RenderKitty, true
+	case sixel:
+		return RenderSixel, true
+	default:
+		return RenderBlocks, false
+	}
+}
+
+// parseGraphicsResponse reports whether the answer to graphicsQuery confirms the Kitty
+// graphics protocol, and whether the device attributes include Sixel graphics.
+func parseGraphicsResponse(response string) (kitty, sixel bool) {
+	kitty = strings.Contains(response, kittyQueryOK)
+	start := strings.Index(response, "\x1b[?")
+	if start < 0 {
+		return kitty, false
+	}
+	attributes, _, ok := strings.Cut(response[start+3:], "c")
+	if !ok {
+		return kitty, false
+	}
+	for _, attribute := range strings.Split(attributes, ";") {
+		if attribute == sixelAttribute {
+			return kitty, true
+		}
+	}
+	return kitty, false
+}
+
+// encodePNG encodes img as a PNG image.
+func encodePNG(img image.Image) ([]byte, error) {
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return data.Bytes(), nil
+}
+
+// KittyRenderer draws the image with the Kitty graphics protocol. The terminal scales it to the cells.
+type KittyRenderer struct{}
+
+// Render transmits the image as PNG in chunks and places it at the cursor.
+func (KittyRenderer) Render(out io.Writer, img image.Image, columns, rows int) error {
+	data, err := encodePNG(img)
+	if err != nil {
+		return err
+	}
+	payload := base64.StdEncoding.EncodeToString(data)
+
+	var sequence strings.Builder
+	for first := true; first || payload != ""; first = false {
+		chunk := payload[:min(kittyChunkSize, len(payload))]
+		payload = payload[len(chunk):]
+		more := 0
+		if payload != "" {
+			more = 1
+		}
+		if first {
+			// q=2 suppresses the terminal's answers, which would otherwise arrive as input.
+			fmt.Fprintf(&sequence, "\x1b_Ga=T,f=100,q=2,c=%d,r=%d,m=%d;%s\x1b\\", columns, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&sequence, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	sequence.WriteString("\n")
+
+	if _, err := io.WriteString(out, sequence.String()); err != nil {
+		return fmt.Errorf("failed to display image: %w", err)
+	}
+	return nil
+}
+
+// ITerm2Renderer draws the image with the iTerm2 inline image protocol. The terminal scales it to the cells.
+type ITerm2Renderer struct{}
+
+// Render sends the image as an inline PNG file.
+func (ITerm2Renderer) Render(out io.Writer, img image.Image, columns, rows int) error {
+	data, err := encodePNG(img)
+	if err != nil {
+		return err
+	}
+
+	sequence := fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0:%s\a\n",
+		len(data), columns, rows, base64.StdEncoding.EncodeToString(data))
+	if _, err := io.WriteString(out, sequence); err != nil {
+		return fmt.Errorf("failed to display image: %w", err)
+	}
+	return nil
+}
+
+// SixelRenderer draws the image with Sixel graphics, quantized to the xterm 256-color palette.
+type SixelRenderer struct {
+	// CellWidth and CellHeight are the size of a character cell in pixels; when zero,
+	// cells of defaultCellWidth x defaultCellHeight pixels are assumed.
+	CellWidth, CellHeight int
+	// Dither spreads the error of quantizing to the palette.
+	Dither Dither
+}
+
+// Render encodes the image as sixels, six pixel rows per band, with one run-length encoded
+// pass per palette color used in the band.
+func (r SixelRenderer) Render(out io.Writer, img image.Image, columns, rows int) error {
+	cellWidth, cellHeight := r.CellWidth, r.CellHeight
+	if cellWidth <= 0 || cellHeight <= 0 {
+		cellWidth, cellHeight = defaultCellWidth, defaultCellHeight
+	}
+	scaled := scaleImage(img, columns*cellWidth, rows*cellHeight)
+	if r.Dither != DitherNone {
+		nearest, spread := Color256.palette()
+		ditherImage(scaled, r.Dither, spread, nearest)
+	}
+	bounds := scaled.Bounds()
+	indexes := make([]int, bounds.Dx()*bounds.Dy())
+	used := map[int]bool{}
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			index := nearestXterm256(scaled.RGBAAt(x, y))
+			indexes[y*bounds.Dx()+x] = index
+			used[index] = true
+		}
+	}
+
+	var sequence strings.Builder
+	fmt.Fprintf(&sequence, "\x1bPq\"1;1;%d;%d", bounds.Dx(), bounds.Dy())
+	for index := 0; index < 256; index++ {
+		if used[index] {
+			c := xterm256Color(index)
+			fmt.Fprintf(&sequence, "#%d;2;%d;%d;%d", index, percent(c.R), percent(c.G), percent(c.B))
+		}
+	}
+
+	sixels := make([]byte, bounds.Dx())
+	for top := 0; top < bounds.Dy(); top += 6 {
+		if top > 0 {
+			sequence.WriteByte('-')
+		}
+		firstColor := true
+		for index := 0; index < 256; index++ {
+			if !used[index] {
+				continue
+			}
+			found := false
+			for x := range sixels {
+				bits := 0
+				for dy := 0; dy < 6 && top+dy < bounds.Dy(); dy++ {
+					if indexes[(top+dy)*bounds.Dx()+x] == index {
+						bits |= 1 << dy
+					}
+				}
+				sixels[x] = byte('?' + bits)
+				found = found || bits != 0
+			}
+			if !found {
+				continue
+			}
+			if !firstColor {
+				sequence.WriteByte('$')
+			}
+			firstColor = false
+			fmt.Fprintf(&sequence, "#%d", index)
+			writeSixelRuns(&sequence, sixels)
+		}
+	}
+	sequence.WriteString("\x1b\\\n")
+
+	if _, err := io.WriteString(out, sequence.String()); err != nil {
+		return fmt.Errorf("failed to display image: %w", err)
+	}
+	return nil
+}
+
+// writeSixelRuns writes sixels, run-length encoding repeats of more than three.
+func writeSixelRuns(sequence *strings.Builder, sixels []byte) {
+	for start := 0; start < len(sixels); {
+		end := start
+		for end < len(sixels) && sixels[end] == sixels[start] {
+			end++
+		}
+		if run := end - start; run > 3 {
+			fmt.Fprintf(sequence, "!%d%c", run, sixels[start])
+		} else {
+			sequence.WriteString(strings.Repeat(string(sixels[start]), run))
+		}
+		start = end
+	}
+}
+
+// percent converts a color channel to the 0-100 range of Sixel color registers.
+func percent(v uint8) int {
+	return (int(v)*100 + 127) / 255
+}
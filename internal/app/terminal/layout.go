@@ -0,0 +1,62 @@
+package terminal
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultCellAspect is the width to height ratio of a character cell when the terminal does not report its pixel size.
+const defaultCellAspect = float64(defaultCellWidth) / defaultCellHeight
+
+// terminalSize is the size of a terminal in character cells and, when the terminal reports it, in pixels.
+type terminalSize struct {
+	columns, rows int
+	width, height int
+}
+
+// cellAspect returns the width to height ratio of a character cell.
+func (s terminalSize) cellAspect() float64 {
+	if s.width <= 0 || s.height <= 0 {
+		return defaultCellAspect
+	}
+	return (float64(s.width) / float64(s.columns)) / (float64(s.height) / float64(s.rows))
+}
+
+// cellSize returns the size of a character cell in pixels, or zeros when the terminal does not report it.
+func (s terminalSize) cellSize() (int, int) {
+	if s.width <= 0 || s.height <= 0 {
+		return 0, 0
+	}
+	return s.width / s.columns, s.height / s.rows
+}
+
+// fitCells returns the largest area of at most maxColumns x maxRows cells that shows an image of
+// imageWidth x imageHeight pixels with its aspect ratio, for cells with the given width to height ratio.
+func fitCells(imageWidth, imageHeight, maxColumns, maxRows int, cellAspect float64) (columns, rows int) {
+	maxColumns, maxRows = max(maxColumns, 1), max(maxRows, 1)
+	if imageWidth <= 0 || imageHeight <= 0 {
+		return maxColumns, maxRows
+	}
+	// The image is imageWidth/imageHeight times wider than high; a cell is cellAspect times.
+	rowsPerColumn := cellAspect * float64(imageHeight) / float64(imageWidth)
+	columns = maxColumns
+	rows = int(math.Round(float64(columns) * rowsPerColumn))
+	if rows > maxRows {
+		rows = maxRows
+		columns = int(math.Round(float64(rows) / rowsPerColumn))
+	}
+	return min(max(columns, 1), maxColumns), max(rows, 1)
+}
+
+// quoteLines returns the number of terminal lines taken by text wrapped at columns.
+func quoteLines(text string, columns int) int {
+	if text == "" {
+		return 0
+	}
+	lines := 0
+	for _, line := range strings.Split(text, "\n") {
+		lines += max(1, (utf8.RuneCountInString(line)+columns-1)/columns)
+	}
+	return lines
+}
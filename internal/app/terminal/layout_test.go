@@ -0,0 +1,67 @@
+package terminal
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"testing"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFitCells(t *testing.T) {
+	tests := []struct {
+		name                    string
+		imageWidth, imageHeight int
+		maxColumns, maxRows     int
+		cellAspect              float64
+		wantColumns, wantRows   int
+	}{
+		{"default size", 40, 30, 80, 30, 0.5, 80, 30},
+		{"limited by rows", 40, 30, 200, 24, 0.5, 64, 24},
+		{"limited by columns", 40, 30, 40, 100, 0.5, 40, 15},
+		{"wide image", 800, 200, 80, 30, 0.5, 80, 10},
+		{"square cells", 40, 30, 80, 30, 1, 40, 30},
+		{"tiny terminal", 40, 30, 1, 1, 0.5, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, rows := fitCells(tt.imageWidth, tt.imageHeight, tt.maxColumns, tt.maxRows, tt.cellAspect)
+			assert.Equal(t, tt.wantColumns, columns)
+			assert.Equal(t, tt.wantRows, rows)
+		})
+	}
+}
+
+func TestQuoteLines(t *testing.T) {
+	assert.Equal(t, 0, quoteLines("", 80))
+	assert.Equal(t, 1, quoteLines("Short quote", 80))
+	assert.Equal(t, 3, quoteLines("A quote wrapped over two lines\nAuthor", 20))
+}
+
+func TestTerminalSize_CellAspect(t *testing.T) {
+	assert.Equal(t, defaultCellAspect, terminalSize{columns: 80, rows: 24}.cellAspect())
+	assert.Equal(t, 0.4, terminalSize{columns: 80, rows: 24, width: 640, height: 480}.cellAspect())
+}
+
+func TestRun_ScalesImageToTerminal(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	mockAPI.On("GetRandomQuoteWithImage", mock.Anything).Return(api.PairResult{Quote: "Random Quote", Image: image.NewRGBA(image.Rect(0, 0, 400, 300))}, nil)
+
+	var columns, rows int
+	renderer := RendererFunc(func(out io.Writer, img image.Image, c, r int) error {
+		columns, rows = c, r
+		return nil
+	})
+	app := NewTerminalApp(mockAPI, WithArgs([]string{"-width", "400", "-height", "300"}), WithOutput(&bytes.Buffer{}), WithRenderer(renderer)).(*TerminalApp)
+	app.terminalSize = func(io.Writer) (terminalSize, bool) {
+		return terminalSize{columns: 100, rows: 30}, true
+	}
+
+	assert.NoError(t, app.Run())
+	// One row is kept for the quote and one for the prompt.
+	assert.Equal(t, 75, columns)
+	assert.Equal(t, 28, rows)
+}
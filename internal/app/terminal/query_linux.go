@@ -0,0 +1,69 @@
+//go:build linux
+
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// queryAttempts is how many reads without an answer, of 100ms each, are awaited.
+const queryAttempts = 5
+
+// queryTerminal sends graphicsQuery to the controlling terminal and returns its answer,
+// reading until the device attributes arrive. The terminal is put in non-canonical mode
+// without echo while waiting, so that the answer is neither line buffered nor shown.
+func queryTerminal() (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to open terminal: %w", err)
+	}
+	defer tty.Close()
+
+	fd := tty.Fd()
+	var saved syscall.Termios
+	if err := ioctl(fd, syscall.TCGETS, &saved); err != nil {
+		return "", fmt.Errorf("failed to read terminal mode: %w", err)
+	}
+	raw := saved
+	raw.Lflag &^= syscall.ICANON | syscall.ECHO
+	raw.Cc[syscall.VMIN] = 0
+	// VTIME is in tenths of a second: each read returns after 100ms without input.
+	raw.Cc[syscall.VTIME] = 1
+	if err := ioctl(fd, syscall.TCSETS, &raw); err != nil {
+		return "", fmt.Errorf("failed to set terminal mode: %w", err)
+	}
+	defer ioctl(fd, syscall.TCSETS, &saved)
+
+	if _, err := tty.WriteString(graphicsQuery); err != nil {
+		return "", fmt.Errorf("failed to query terminal: %w", err)
+	}
+
+	var response strings.Builder
+	buf := make([]byte, 256)
+	for attempts := 0; attempts < queryAttempts; {
+		n, err := tty.Read(buf)
+		if err != nil {
+			return "", fmt.Errorf("failed to read terminal answer: %w", err)
+		}
+		if n == 0 {
+			attempts++
+			continue
+		}
+		response.Write(buf[:n])
+		if _, attributes, ok := strings.Cut(response.String(), "\x1b[?"); ok && strings.Contains(attributes, "c") {
+			return response.String(), nil
+		}
+	}
+	return response.String(), fmt.Errorf("terminal did not answer the device attributes query")
+}
+
+func ioctl(fd uintptr, request uintptr, termios *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(unsafe.Pointer(termios))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package terminal
+
+import "errors"
+
+// queryTerminal is only implemented on Linux; elsewhere graphics protocols are detected
+// from the environment alone.
+func queryTerminal() (string, error) {
+	return "", errors.New("terminal queries are not supported on this platform")
+}
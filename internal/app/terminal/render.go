@@ -0,0 +1,270 @@
+package terminal
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/ramyad/tucows/internal/config"
+	xdraw "golang.org/x/image/draw"
+)
+
+// RenderMode selects the characters or the graphics protocol the image is drawn with.
+type RenderMode int
+
+const (
+	// RenderBlocks draws every pixel as two spaces with a background color.
+	RenderBlocks RenderMode = iota
+	// RenderHalfBlocks draws two pixels per character with the ▀ and ▄ half blocks.
+	RenderHalfBlocks
+	// RenderQuadrants draws 2x2 pixels per character with the Unicode quadrant blocks.
+	RenderQuadrants
+	// RenderSextants draws 2x3 pixels per character with the Unicode sextant blocks.
+	RenderSextants
+	// RenderSixel draws real pixels with the Sixel graphics protocol.
+	RenderSixel
+	// RenderKitty draws real pixels with the Kitty graphics protocol.
+	RenderKitty
+	// RenderITerm2 draws real pixels with the iTerm2 inline image protocol.
+	RenderITerm2
+	// RenderASCII draws the image as ASCII art without colors.
+	RenderASCII
+	// RenderBraille draws 2x4 pixels per character with Braille patterns without colors.
+	RenderBraille
+)
+
+// String returns the -render value selecting the mode.
+func (m RenderMode) String() string {
+	switch m {
+	case RenderHalfBlocks:
+		return config.RenderHalf
+	case RenderQuadrants:
+		return config.RenderQuadrant
+	case RenderSextants:
+		return config.RenderSextant
+	case RenderSixel:
+		return config.RenderSixel
+	case RenderKitty:
+		return config.RenderKitty
+	case RenderITerm2:
+		return config.RenderITerm2
+	case RenderASCII:
+		return config.RenderASCII
+	case RenderBraille:
+		return config.RenderBraille
+	default:
+		return config.RenderBlocks
+	}
+}
+
+// resolveRenderMode returns the mode selected by a terminal.render value. For config.RenderAuto,
+// a graphics protocol is detected with DetectGraphics when out is a terminal with colors,
+// falling back to DetectRenderMode.
+func resolveRenderMode(value string, colorMode ColorMode, getenv func(string) string, out io.Writer) RenderMode {
+	switch value {
+	case config.RenderBlocks:
+		return RenderBlocks
+	case config.RenderHalf:
+		return RenderHalfBlocks
+	case config.RenderQuadrant:
+		return RenderQuadrants
+	case config.RenderSextant:
+		return RenderSextants
+	case config.RenderSixel:
+		return RenderSixel
+	case config.RenderKitty:
+		return RenderKitty
+	case config.RenderITerm2:
+		return RenderITerm2
+	case config.RenderASCII:
+		return RenderASCII
+	case config.RenderBraille:
+		return RenderBraille
+	}
+
+	if colorMode != ColorNone && isTerminal(out) {
+		if mode, ok := DetectGraphics(getenv, queryTerminal); ok {
+			return mode
+		}
+	}
+	return DetectRenderMode(colorMode, getenv)
+}
+
+// DetectRenderMode returns the render mode suited to the terminal: ASCII art without colors,
+// half blocks with colors and a UTF-8 locale, and blocks otherwise. Quadrants, sextants and
+// Braille patterns are only used when selected, as many fonts lack some of their characters.
+func DetectRenderMode(colorMode ColorMode, getenv func(string) string) RenderMode {
+	switch {
+	case colorMode == ColorNone:
+		return RenderASCII
+	case !isUTF8Locale(getenv):
+		return RenderBlocks
+	default:
+		return RenderHalfBlocks
+	}
+}
+
+// isUTF8Locale reports whether the locale selected by LC_ALL, LC_CTYPE or LANG uses UTF-8.
+func isUTF8Locale(getenv func(string) string) bool {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getenv(key); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return false
+}
+
+// cellGlyphs describes how a character cell is split into pixels. glyphs holds the
+// character for every mask of foreground pixels, where bit i is pixel i in row-major order.
+type cellGlyphs struct {
+	width, height int
+	glyphs        []string
+}
+
+var (
+	halfBlocks = cellGlyphs{1, 2, []string{" ", "▀", "▄", "█"}}
+	quadrants  = cellGlyphs{2, 2, []string{
+		" ", "▘", "▝", "▀", "▖", "▌", "▞", "▛",
+		"▗", "▚", "▐", "▜", "▄", "▙", "▟", "█",
+	}}
+	sextants = cellGlyphs{2, 3, sextantGlyphs()}
+)
+
+// sextantGlyphs returns the sextant characters, which are encoded from U+1FB00 in mask
+// order, except for the masks drawn with the existing space, ▌, ▐ and █ characters.
+func sextantGlyphs() []string {
+	glyphs := make([]string, 64)
+	next := rune(0x1FB00)
+	for mask := range glyphs {
+		switch mask {
+		case 0:
+			glyphs[mask] = " "
+		case 0b010101:
+			glyphs[mask] = "▌"
+		case 0b101010:
+			glyphs[mask] = "▐"
+		case 0b111111:
+			glyphs[mask] = "█"
+		default:
+			glyphs[mask] = string(next)
+			next++
+		}
+	}
+	return glyphs
+}
+
+// scaleImage scales img to width x height pixels.
+func scaleImage(img image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+	return dst
+}
+
+// renderBlocks draws every pixel of scaled as two characters.
+func renderBlocks(scaled *image.RGBA, colorMode ColorMode) []string {
+	width, height := scaled.Bounds().Dx(), scaled.Bounds().Dy()
+	rows := make([]string, height)
+	var row strings.Builder
+	for y := range rows {
+		row.Reset()
+		for x := 0; x < width; x++ {
+			c := scaled.RGBAAt(x, y)
+			if colorMode == ColorNone {
+				row.WriteByte(shade(c))
+				row.WriteByte(shade(c))
+				continue
+			}
+			row.WriteString(colorMode.background(c))
+			row.WriteString("  ")
+			row.WriteString(resetColor)
+		}
+		rows[y] = row.String()
+	}
+	return rows
+}
+
+// renderCells draws scaled as characters, each showing the pixels of a cell. The pixels
+// of a cell are split into a foreground and a background group along the color channel that
+// varies most, and each group is drawn with its average color. Without colors, the pixels
+// brighter than mid-gray form the foreground.
+func renderCells(scaled *image.RGBA, cell cellGlyphs, colorMode ColorMode) []string {
+	columns, rows := scaled.Bounds().Dx()/cell.width, scaled.Bounds().Dy()/cell.height
+	lines := make([]string, rows)
+	pixels := make([]color.RGBA, cell.width*cell.height)
+	var line strings.Builder
+	for row := range lines {
+		line.Reset()
+		for column := 0; column < columns; column++ {
+			for i := range pixels {
+				pixels[i] = scaled.RGBAAt(column*cell.width+i%cell.width, row*cell.height+i/cell.width)
+			}
+
+			if colorMode == ColorNone {
+				mask := 0
+				for i, c := range pixels {
+					if color.GrayModel.Convert(c).(color.Gray).Y >= 128 {
+						mask |= 1 << i
+					}
+				}
+				line.WriteString(cell.glyphs[mask])
+				continue
+			}
+
+			mask, fg, bg := splitCell(pixels)
+			if mask != 0 {
+				line.WriteString(colorMode.foreground(fg))
+			}
+			line.WriteString(colorMode.background(bg))
+			line.WriteString(cell.glyphs[mask])
+		}
+		if colorMode != ColorNone {
+			line.WriteString(resetColor)
+		}
+		lines[row] = line.String()
+	}
+	return lines
+}
+
+// splitCell splits pixels at the middle of the color channel with the largest range.
+// It returns the mask of the pixels above the middle and the average colors of both groups;
+// the mask is 0 and bg the average color when all pixels are equal.
+func splitCell(pixels []color.RGBA) (mask int, fg, bg color.RGBA) {
+	channel := func(c color.RGBA, i int) int {
+		return int([3]uint8{c.R, c.G, c.B}[i])
+	}
+	widest, widestRange, middle := 0, -1, 0
+	for i := 0; i < 3; i++ {
+		low, high := 255, 0
+		for _, c := range pixels {
+			low, high = min(low, channel(c, i)), max(high, channel(c, i))
+		}
+		if high-low > widestRange {
+			widest, widestRange, middle = i, high-low, (low+high)/2
+		}
+	}
+
+	var fgSum, bgSum [3]int
+	var fgCount, bgCount int
+	for i, c := range pixels {
+		sum := &bgSum
+		if widestRange > 0 && channel(c, widest) > middle {
+			mask |= 1 << i
+			sum = &fgSum
+			fgCount++
+		} else {
+			bgCount++
+		}
+		sum[0] += int(c.R)
+		sum[1] += int(c.G)
+		sum[2] += int(c.B)
+	}
+	average := func(sum [3]int, count int) color.RGBA {
+		if count == 0 {
+			return color.RGBA{A: 255}
+		}
+		return color.RGBA{uint8(sum[0] / count), uint8(sum[1] / count), uint8(sum[2] / count), 255}
+	}
+	return mask, average(fgSum, fgCount), average(bgSum, bgCount)
+}
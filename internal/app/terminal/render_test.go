@@ -0,0 +1,88 @@
+package terminal
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectRenderMode(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	assert.Equal(t, RenderHalfBlocks, DetectRenderMode(Color256, env(map[string]string{"LANG": "en_US.UTF-8"})))
+	assert.Equal(t, RenderHalfBlocks, DetectRenderMode(Color16, env(map[string]string{"LC_ALL": "C.utf8", "LANG": "C"})))
+	assert.Equal(t, RenderBlocks, DetectRenderMode(ColorTrueColor, env(map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"})))
+	assert.Equal(t, RenderBlocks, DetectRenderMode(ColorTrueColor, env(nil)))
+	assert.Equal(t, RenderASCII, DetectRenderMode(ColorNone, env(map[string]string{"LANG": "en_US.UTF-8"})))
+}
+
+func TestSextantGlyphs(t *testing.T) {
+	glyphs := sextantGlyphs()
+	assert.Equal(t, "\U0001FB00", glyphs[0b000001])
+	assert.Equal(t, "\U0001FB13", glyphs[0b010100])
+	assert.Equal(t, "\U0001FB14", glyphs[0b010110])
+	assert.Equal(t, "\U0001FB3B", glyphs[0b111110])
+	assert.Equal(t, "▌", glyphs[0b010101])
+	assert.Equal(t, "█", glyphs[0b111111])
+}
+
+func TestSplitCell(t *testing.T) {
+	red, blue := color.RGBA{200, 0, 0, 255}, color.RGBA{0, 0, 100, 255}
+	mask, fg, bg := splitCell([]color.RGBA{red, blue, blue, red})
+	assert.Equal(t, 0b1001, mask)
+	assert.Equal(t, red, fg)
+	assert.Equal(t, blue, bg)
+
+	mask, _, bg = splitCell([]color.RGBA{blue, blue})
+	assert.Equal(t, 0, mask)
+	assert.Equal(t, blue, bg)
+}
+
+func TestTextRenderer_RenderModes(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	// pixels builds an image from rows of pixels: W is white, R is red and anything else black.
+	pixels := func(rows ...string) image.Image {
+		img := image.NewRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+		for y, row := range rows {
+			for x, p := range row {
+				switch p {
+				case 'W':
+					img.Set(x, y, color.White)
+				case 'R':
+					img.Set(x, y, red)
+				default:
+					img.Set(x, y, color.Black)
+				}
+			}
+		}
+		return img
+	}
+
+	tests := []struct {
+		name      string
+		img       image.Image
+		render    RenderMode
+		colorMode ColorMode
+		want      string
+	}{
+		{"half blocks", pixels("WK", "RR"), RenderHalfBlocks, ColorTrueColor, "" +
+			"\x1b[38;2;255;255;255m\x1b[48;2;255;0;0m▀\x1b[38;2;255;0;0m\x1b[48;2;0;0;0m▄\x1b[0m\n"},
+		{"half blocks without colors", pixels("WK", "RR"), RenderHalfBlocks, ColorNone, "▀ \n"},
+		{"quadrants", pixels("WKKK", "KKRR"), RenderQuadrants, Color256, "" +
+			"\x1b[38;5;231m\x1b[48;5;16m▘\x1b[38;5;196m\x1b[48;5;16m▄\x1b[0m\n"},
+		{"quadrants without colors", pixels("WKKW", "KKWW"), RenderQuadrants, ColorNone, "▘▟\n"},
+		{"sextants", pixels("WKKK", "WKKK", "KKKK"), RenderSextants, Color16, "" +
+			"\x1b[97m\x1b[40m\U0001FB04\x1b[40m \x1b[0m\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, NewRenderer(tt.render, tt.colorMode, DitherNone).Render(&out, tt.img, 2, 1))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
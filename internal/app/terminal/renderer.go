@@ -0,0 +1,102 @@
+package terminal
+
+import (
+	"image"
+	"io"
+
+	"github.com/ramyad/tucows/internal/config"
+)
+
+// Renderer draws an image to a terminal, scaled to fill columns x rows character cells.
+type Renderer interface {
+	Render(out io.Writer, img image.Image, columns, rows int) error
+}
+
+// RendererFunc adapts a function to the Renderer interface.
+type RendererFunc func(out io.Writer, img image.Image, columns, rows int) error
+
+// Render calls f.
+func (f RendererFunc) Render(out io.Writer, img image.Image, columns, rows int) error {
+	return f(out, img, columns, rows)
+}
+
+// NewRenderer returns the renderer of mode. Character modes use the closest colors of colorMode;
+// the graphics protocols always draw real colors, and ASCII art and Braille patterns none.
+// Renderers reducing the image to fewer colors or characters do so with dither.
+func NewRenderer(mode RenderMode, colorMode ColorMode, dither Dither) Renderer {
+	switch mode {
+	case RenderASCII:
+		return ASCIIRenderer{Ramp: config.DefaultRamp, Dither: dither}
+	case RenderBraille:
+		return BrailleRenderer{Dither: dither}
+	case RenderSixel:
+		return SixelRenderer{Dither: dither}
+	case RenderKitty:
+		return KittyRenderer{}
+	case RenderITerm2:
+		return ITerm2Renderer{}
+	case RenderHalfBlocks:
+		return textRenderer{cell: &halfBlocks, colorMode: colorMode, dither: dither}
+	case RenderQuadrants:
+		return textRenderer{cell: &quadrants, colorMode: colorMode, dither: dither}
+	case RenderSextants:
+		return textRenderer{cell: &sextants, colorMode: colorMode, dither: dither}
+	default:
+		return textRenderer{colorMode: colorMode, dither: dither}
+	}
+}
+
+// rendererFromConfig returns the renderer selected by the terminal settings for out.
+func rendererFromConfig(cfg config.TerminalConfig, getenv func(string) string, out io.Writer) Renderer {
+	colorMode := resolveColorMode(cfg.Color, getenv, out)
+	dither := parseDither(cfg.Dither)
+	switch mode := resolveRenderMode(cfg.Render, colorMode, getenv, out); mode {
+	case RenderASCII:
+		return ASCIIRenderer{Ramp: cfg.Ramp, Edges: cfg.Edges, Dither: dither}
+	case RenderSixel:
+		size, _ := getTerminalSize(out)
+		cellWidth, cellHeight := size.cellSize()
+		return SixelRenderer{CellWidth: cellWidth, CellHeight: cellHeight, Dither: dither}
+	default:
+		return NewRenderer(mode, colorMode, dither)
+	}
+}
+
+// textRenderer draws the image with characters and ANSI colors: with the glyphs of cell,
+// or as blocks of two characters per pixel when cell is nil. Without colors, blocks become
+// a brightness ramp and the other glyphs show the bright pixels.
+type textRenderer struct {
+	cell      *cellGlyphs
+	colorMode ColorMode
+	dither    Dither
+}
+
+// Render draws the image line by line.
+func (r textRenderer) Render(out io.Writer, img image.Image, columns, rows int) error {
+	var lines []string
+	if r.cell == nil {
+		scaled := scaleImage(img, max(columns/2, 1), rows)
+		r.reduce(scaled, len(shadeRamp))
+		lines = renderBlocks(scaled, r.colorMode)
+	} else {
+		scaled := scaleImage(img, columns*r.cell.width, rows*r.cell.height)
+		r.reduce(scaled, 2)
+		lines = renderCells(scaled, *r.cell, r.colorMode)
+	}
+	return writeLines(out, lines)
+}
+
+// reduce dithers img to the palette of the color mode, or to grays levels without colors.
+// Without dithering, the closest colors are picked while drawing.
+func (r textRenderer) reduce(img *image.RGBA, grays int) {
+	if r.dither == DitherNone {
+		return
+	}
+	if r.colorMode == ColorNone {
+		ditherImage(img, r.dither, 1/float64(grays-1), nearestGray(grays))
+		return
+	}
+	if nearest, spread := r.colorMode.palette(); nearest != nil {
+		ditherImage(img, r.dither, spread, nearest)
+	}
+}
@@ -0,0 +1,111 @@
+package terminal
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"image"
+	"image/color"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var update = flag.Bool("update", false, "Update the golden files in testdata")
+
+// gradient returns a small image with a distinct color for every pixel.
+func gradient() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 85), uint8(y * 127), uint8(255 - x*60), 255})
+		}
+	}
+	return img
+}
+
+func TestRenderers_Golden(t *testing.T) {
+	modes := []RenderMode{RenderBlocks, RenderHalfBlocks, RenderQuadrants, RenderSextants, RenderSixel, RenderKitty, RenderITerm2, RenderASCII, RenderBraille}
+	for _, mode := range modes {
+		t.Run(mode.String(), func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, NewRenderer(mode, ColorTrueColor, DitherFloydSteinberg).Render(&out, gradient(), 4, 1))
+
+			path := filepath.Join("testdata", mode.String()+".golden")
+			if *update {
+				assert.NoError(t, os.WriteFile(path, out.Bytes(), 0o644))
+			}
+			golden, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, string(golden), out.String())
+		})
+	}
+}
+
+func TestDetectGraphics(t *testing.T) {
+	noQuery := func() (string, error) { return "", errors.New("no terminal") }
+	answer := func(response string) func() (string, error) {
+		return func() (string, error) { return response, nil }
+	}
+
+	tests := []struct {
+		name   string
+		env    map[string]string
+		query  func() (string, error)
+		want   RenderMode
+		wantOK bool
+	}{
+		{"kitty TERM", map[string]string{"TERM": "xterm-kitty"}, noQuery, RenderKitty, true},
+		{"iTerm2", map[string]string{"TERM_PROGRAM": "iTerm.app"}, noQuery, RenderITerm2, true},
+		{"tmux", map[string]string{"TERM": "screen-256color", "TMUX": "/tmp/tmux", "KITTY_WINDOW_ID": "1"}, answer(kittyQueryOK), RenderBlocks, false},
+		{"kitty answer", map[string]string{"TERM": "xterm-256color"}, answer(kittyQueryOK + "\x1b\\\x1b[?62;22c"), RenderKitty, true},
+		{"sixel attribute", map[string]string{"TERM": "xterm-256color"}, answer("\x1b[?63;1;2;4;6;9;15;22c"), RenderSixel, true},
+		{"no graphics", map[string]string{"TERM": "xterm-256color"}, answer("\x1b[?62;22;42c"), RenderBlocks, false},
+		{"no answer", map[string]string{"TERM": "xterm-256color"}, noQuery, RenderBlocks, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			mode, ok := DetectGraphics(getenv, tt.query)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, mode)
+		})
+	}
+}
+
+func TestKittyRenderer_Chunks(t *testing.T) {
+	// A noisy image compresses poorly, so that its PNG needs several chunks.
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	rand.New(rand.NewSource(1)).Read(img.Pix)
+
+	var out bytes.Buffer
+	assert.NoError(t, KittyRenderer{}.Render(&out, img, 8, 2))
+	commands := bytes.Split(bytes.TrimSuffix(out.Bytes(), []byte("\n")), []byte("\x1b\\"))
+	commands = commands[:len(commands)-1]
+	assert.Greater(t, len(commands), 1)
+	assert.True(t, bytes.HasPrefix(commands[0], []byte("\x1b_Ga=T,f=100,q=2,c=8,r=2,m=1;")))
+	for _, command := range commands[1 : len(commands)-1] {
+		assert.True(t, bytes.HasPrefix(command, []byte("\x1b_Gm=1;")))
+	}
+	assert.True(t, bytes.HasPrefix(commands[len(commands)-1], []byte("\x1b_Gm=0;")))
+}
+
+func TestRun_WithRenderer(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	mockAPI.On("GetRandomQuoteWithImage", mock.Anything).Return(api.PairResult{Quote: "Random Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}, nil)
+	renderer := RendererFunc(func(out io.Writer, img image.Image, columns, rows int) error {
+		_, err := io.WriteString(out, "[image]\n")
+		return err
+	})
+
+	var out bytes.Buffer
+	err := NewTerminalApp(mockAPI, WithArgs(nil), WithOutput(&out), WithRenderer(renderer)).Run()
+	assert.NoError(t, err)
+	assert.Equal(t, "Random Quote\n[image]\n", out.String())
+}
@@ -0,0 +1,16 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package terminal
+
+import (
+	"io"
+	"os"
+)
+
+// getTerminalSize is not supported on this platform; images keep their requested size.
+func getTerminalSize(io.Writer) (terminalSize, bool) {
+	return terminalSize{}, false
+}
+
+// notifyResize is not supported on this platform.
+func notifyResize(chan<- os.Signal) {}
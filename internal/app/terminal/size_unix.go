@@ -0,0 +1,32 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package terminal
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// getTerminalSize returns the size of the terminal out writes to, as reported by TIOCGWINSZ.
+func getTerminalSize(out io.Writer) (terminalSize, bool) {
+	file, ok := out.(*os.File)
+	if !ok || !isTerminal(out) {
+		return terminalSize{}, false
+	}
+	var ws struct{ rows, columns, width, height uint16 }
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws))); errno != 0 {
+		return terminalSize{}, false
+	}
+	if ws.columns == 0 || ws.rows == 0 {
+		return terminalSize{}, false
+	}
+	return terminalSize{columns: int(ws.columns), rows: int(ws.rows), width: int(ws.width), height: int(ws.height)}, true
+}
+
+// notifyResize relays SIGWINCH, sent when the terminal is resized, to c.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
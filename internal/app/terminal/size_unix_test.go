@@ -0,0 +1,61 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// syncBuffer is a bytes.Buffer that can be written and read concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSlideshow_RedrawsOnResize(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	pairs := make(chan api.Pair, 1)
+	pairs <- api.Pair{PairResult: api.PairResult{Quote: "First Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}}
+	mockAPI.On("Subscribe", 10*time.Second, mock.Anything).Return((<-chan api.Pair)(pairs))
+
+	out := &syncBuffer{}
+	renderer := RendererFunc(func(out io.Writer, img image.Image, columns, rows int) error {
+		_, err := io.WriteString(out, "[image]\n")
+		return err
+	})
+	app := NewTerminalApp(mockAPI, WithArgs([]string{"-interval", "10s"}), WithOutput(out), WithRenderer(renderer)).(*TerminalApp)
+	assert.NoError(t, app.ParseRequest())
+
+	done := make(chan error)
+	go func() { done <- app.Slideshow(context.Background()) }()
+
+	assert.Eventually(t, func() bool { return out.String() == "First Quote\n[image]\n" }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGWINCH))
+	assert.Eventually(t, func() bool { return out.String() == "First Quote\n[image]\nFirst Quote\n[image]\n" }, time.Second, 10*time.Millisecond)
+
+	close(pairs)
+	assert.NoError(t, <-done)
+}
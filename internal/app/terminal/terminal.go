@@ -1,130 +1,480 @@
 package terminal
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"image"
-	"log"
+	"io"
+	"log/slog"
 	"os"
-	"strings"
+	"os/signal"
+	"path/filepath"
+	"time"
 
 	"github.com/fogleman/gg"
 	"github.com/ramyad/tucows/internal/api"
 	"github.com/ramyad/tucows/internal/api/imageapi"
-	"github.com/ramyad/tucows/internal/api/quoteapi"
 	"github.com/ramyad/tucows/internal/app"
+	"github.com/ramyad/tucows/internal/card"
+	"github.com/ramyad/tucows/internal/config"
+	"github.com/ramyad/tucows/internal/logging"
 	"github.com/ramyad/tucows/internal/shared"
 )
 
 const (
 	DefaultImageWidth  = 40
 	DefaultImageHeight = 30
+	// BatchTimeout is the deadline shared by all pairs generated with -count.
+	BatchTimeout = time.Minute
+	// clearScreen clears the terminal and moves the cursor home between slideshow pairs.
+	clearScreen = "\x1b[2J\x1b[H"
+	// DefaultLogFile keeps log records from interleaving with the rendered image.
+	DefaultLogFile = "app.log"
+)
+
+// Exit codes of the terminal application, by the kind of the error that stopped it.
+const (
+	ExitOK                  = 0
+	ExitFailure             = 1
+	ExitInvalidInput        = 2
+	ExitUpstreamUnavailable = 3
+	ExitUpstreamBadResponse = 4
+	ExitDecodeFailure       = 5
+	ExitTimeout             = 6
+	ExitRateLimited         = 7
 )
 
 // TerminalApp implements the AppInterface for the terminal application.
 type TerminalApp struct {
-	api     api.API
-	options app.Options
+	api       api.API
+	catalog   imageapi.ImageCatalog
+	logger    *slog.Logger
+	requestID string
+	out       io.Writer
+	renderer  Renderer
+	// terminalSize returns the size of the terminal out writes to, if it is one.
+	terminalSize func(io.Writer) (terminalSize, bool)
+	args         []string
+	config       *config.Config
+	options      app.Options
+	listCatalog  bool
+	catalogQuery imageapi.CatalogQuery
+	outputPath   string
+	count        int
+	outputDir    string
+	interval     time.Duration
 }
 
 // Ensure that *TerminalApp implements app.APP interface
 var _ app.App = (*TerminalApp)(nil)
 
+// Option configures optional behaviour of the TerminalApp.
+type Option func(*TerminalApp)
+
+// WithArgs sets the command-line arguments parsed by the TerminalApp instead of os.Args[1:].
+func WithArgs(args []string) Option {
+	return func(t *TerminalApp) {
+		t.args = args
+	}
+}
+
+// WithCatalog enables listing and previewing images from the given image catalog.
+func WithCatalog(catalog imageapi.ImageCatalog) Option {
+	return func(t *TerminalApp) {
+		t.catalog = catalog
+	}
+}
+
+// WithLogger sets the logger of the TerminalApp. By default slog.Default() is used.
+func WithLogger(logger *slog.Logger) Option {
+	return func(t *TerminalApp) {
+		t.logger = logger
+	}
+}
+
+// WithOutput sets where the quote and image are displayed instead of os.Stdout.
+// Colors are detected only when out is a terminal.
+func WithOutput(out io.Writer) Option {
+	return func(t *TerminalApp) {
+		t.out = out
+	}
+}
+
+// WithRenderer sets how images are drawn instead of the renderer selected by -render and -color.
+func WithRenderer(renderer Renderer) Option {
+	return func(t *TerminalApp) {
+		t.renderer = renderer
+	}
+}
+
 // NewTerminalApp creates a new instance of the TerminalApp.
-func NewTerminalApp(api api.API) app.App {
-	return &TerminalApp{
-		api: api,
+func NewTerminalApp(api api.API, opts ...Option) app.App {
+	t := &TerminalApp{
+		api:          api,
+		args:         os.Args[1:],
+		requestID:    logging.NewRequestID(),
+		out:          os.Stdout,
+		terminalSize: getTerminalSize,
+	}
+	for _, opt := range opts {
+		opt(t)
 	}
+	t.logger = logging.OrDefault(t.logger)
+	return t
 }
 
 // Run executes the main logic for the terminal application,
 // including parsing input, fetching a random quote and image,
 // and displaying the content in the terminal.
 func (t *TerminalApp) Run() error {
-	logFile, err := os.Create("app.log")
-	if err != nil {
-		return fmt.Errorf("failed to create log file: %v", err)
-	}
-	defer logFile.Close()
-	log.SetOutput(logFile)
-
-	log.Printf("[%s] Starting terminal application...", shared.LogLevelInfo)
+	ctx := t.context(context.Background())
+	t.logger.InfoContext(ctx, "Starting terminal application")
 
 	if err := t.ParseRequest(); err != nil {
-		log.Printf("[%s] Failed to parse input: %v", shared.LogLevelError, err)
+		t.logger.ErrorContext(ctx, "Failed to parse input", logging.Err(err))
 		return err
 	}
 
-	if t.options.ImageWidth > DefaultImageWidth || t.options.ImageHeight > DefaultImageHeight {
-		log.Printf("[%s] Requested image size exceeds default terminal size.", shared.LogLevelWarning)
+	if t.listCatalog {
+		t.logger.InfoContext(ctx, "Listing image catalog", "page", t.catalogQuery.Page, "limit", t.catalogQuery.Limit)
+		if err := t.ListCatalog(); err != nil {
+			t.logger.ErrorContext(ctx, "Failed to list image catalog", logging.Err(err))
+			return err
+		}
+		return nil
+	}
+
+	if t.interval > 0 {
+		t.logger.InfoContext(ctx, "Starting slideshow", "interval", t.interval)
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+		if err := t.Slideshow(ctx); err != nil {
+			t.logger.ErrorContext(ctx, "Failed to run slideshow", logging.Err(err))
+			return err
+		}
+		return nil
+	}
+
+	if t.count > 1 || t.outputDir != "" {
+		t.logger.InfoContext(ctx, "Generating quote and image pairs", "count", t.count)
+		if err := t.GenerateBatch(); err != nil {
+			t.logger.ErrorContext(ctx, "Failed to generate quote and image pairs", logging.Err(err))
+			return err
+		}
+		return nil
 	}
 
-	log.Printf("[%s] Fetching random quote and image...", shared.LogLevelInfo)
+	t.logger.InfoContext(ctx, "Fetching random quote and image")
 	randomQuote, randomImage, err := t.FetchQuoteAndImage()
 	if err != nil {
-		log.Printf("[%s] Failed to fetch random quote and image: %v", shared.LogLevelError, err)
+		t.logger.ErrorContext(ctx, "Failed to fetch random quote and image", logging.Err(err))
 		return err
 	}
 
-	log.Printf("[%s] Displaying quote and image content in terminal...", shared.LogLevelInfo)
+	t.logger.InfoContext(ctx, "Displaying quote and image content in terminal")
 	if err := t.DisplayContent(randomQuote, randomImage); err != nil {
-		log.Printf("[%s] Failed to display terminal image: %v", shared.LogLevelError, err)
+		t.logger.ErrorContext(ctx, "Failed to display terminal image", logging.Err(err))
 		return err
 	}
 
-	log.Printf("[%s] Terminal application completed successfully.", shared.LogLevelInfo)
+	t.logger.InfoContext(ctx, "Terminal application completed successfully")
 	return nil
 }
 
-// ParseRequest parses the command-line flags and returns the Options.
+// context returns parent carrying the request ID of this run, so that the records logged
+// by the API for it can be correlated.
+func (t *TerminalApp) context(parent context.Context) context.Context {
+	return logging.WithAttrs(parent, logging.RequestIDKey, t.requestID)
+}
+
+// configFlags are the configuration settings that can be given as terminal flags.
+var configFlags = []string{
+	"log.level", "log.format", "log.file",
+	"quote.provider", "quote.category",
+	"image.provider", "image.width", "image.height", "image.filters", "image.id",
+	"card.enabled", "card.align", "card.valign", "card.background", "card.margin",
+	"terminal.interval", "terminal.color", "terminal.render", "terminal.ramp", "terminal.edges", "terminal.dither",
+}
+
+// ParseRequest parses the command-line flags, loads the configuration and sets the Options.
 func (t *TerminalApp) ParseRequest() error {
-	var filters string
+	cfg, err := loadConfig(t.args, t.flagSet())
+	if err != nil {
+		return err
+	}
+	t.config = cfg
+	t.interval = cfg.Terminal.Interval
+	if t.renderer == nil {
+		t.renderer = rendererFromConfig(cfg.Terminal, os.Getenv, t.out)
+	}
 
-	flag.IntVar(&t.options.QuoteCategory, "category", 0, "Specify the quote category")
-	flag.IntVar(&t.options.ImageWidth, "width", DefaultImageWidth, "Specify the image width")
-	flag.IntVar(&t.options.ImageHeight, "height", DefaultImageHeight, "Specify the image height")
-	flag.StringVar(&filters, "filters", "", "Specify image filters as a comma-separated list: grayscale, blur")
-	flag.Parse()
+	t.options, err = app.NewOptionsFromConfig(cfg)
+	if err != nil {
+		return shared.Wrap(shared.ErrInvalidInput, err)
+	}
+	if t.options.Card == nil && (t.outputPath != "" || t.outputDir != "") {
+		t.options.Card, err = cfg.Card.Builder()
+		if err != nil {
+			return shared.Wrap(shared.ErrInvalidInput, err)
+		}
+	}
 
-	t.options.Filters = imageapi.ImageFilters(strings.Split(filters, ","))
+	if _, err := t.options.PairRequest(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// flagSet returns the flags that only the terminal application has, bound to the app's fields.
+func (t *TerminalApp) flagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet("terminal", flag.ContinueOnError)
+	flags.BoolVar(&t.listCatalog, "list", false, "List catalog images instead of displaying a quote")
+	flags.IntVar(&t.catalogQuery.Page, "page", imageapi.DefaultCatalogPage, "Specify the catalog page to list")
+	flags.IntVar(&t.catalogQuery.Limit, "limit", imageapi.DefaultCatalogLimit, "Specify the number of catalog images per page")
+	flags.StringVar(&t.catalogQuery.Author, "author", "", "Filter listed catalog images by author")
+	flags.StringVar(&t.outputPath, "output", "", "Save the quote card as a PNG file at the given path")
+	flags.IntVar(&t.count, "count", 1, "Specify the number of quote and image pairs to generate")
+	flags.StringVar(&t.outputDir, "output-dir", "", "Save the generated pairs as quote card PNG files in the given directory")
+	return flags
+}
+
+// loadConfig loads the configuration from args with the terminal defaults, accepting the
+// terminal-only flags registered on flags.
+func loadConfig(args []string, flags *flag.FlagSet) (*config.Config, error) {
+	defaults := config.Default()
+	defaults.Image.Width = DefaultImageWidth
+	defaults.Image.Height = DefaultImageHeight
+	defaults.Log.File = DefaultLogFile
+	cfg, err := config.Load(args, config.WithFlagSet(flags), config.WithDefaults(defaults), config.WithFlags(configFlags...))
+	if err != nil {
+		return nil, shared.Wrap(shared.ErrInvalidInput, err)
+	}
+	return cfg, nil
+}
+
+// ExitCode returns the exit code reporting err: ExitOK when it is nil, the code of its kind
+// when it has one and ExitFailure otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	switch shared.Kind(err) {
+	case shared.ErrInvalidInput:
+		return ExitInvalidInput
+	case shared.ErrUpstreamUnavailable:
+		return ExitUpstreamUnavailable
+	case shared.ErrUpstreamBadResponse:
+		return ExitUpstreamBadResponse
+	case shared.ErrDecode:
+		return ExitDecodeFailure
+	case shared.ErrTimeout:
+		return ExitTimeout
+	case shared.ErrRateLimited:
+		return ExitRateLimited
+	default:
+		return ExitFailure
+	}
+}
+
+// LoadConfig parses the command-line arguments and returns the loaded configuration,
+// so the API can be built before the app runs. It neither validates the request nor
+// selects a renderer; Run does both.
+func LoadConfig(args []string) (*config.Config, error) {
+	return loadConfig(args, new(TerminalApp).flagSet())
+}
+
 // FetchQuoteAndImage fetches a random quote and image for the terminal application.
 func (t *TerminalApp) FetchQuoteAndImage() (string, image.Image, error) {
-	quoteConfigBuilder := quoteapi.NewQuoteConfigBuilder().WithKey(t.options.QuoteCategory)
-	imageConfigBuilder := imageapi.NewImageConfigBuilder().WithWidth(t.options.ImageWidth).WithHeight(t.options.ImageHeight).WithFilters(t.options.Filters)
+	req, err := t.options.PairRequest()
+	if err != nil {
+		return "", nil, err
+	}
 
-	randomQuote, randomImage, err := t.api.GetRandomQuoteWithImage(quoteConfigBuilder, imageConfigBuilder)
+	if t.options.Card != nil {
+		cardImage, err := t.api.GetQuoteCard(t.context(context.Background()), req, t.options.Card)
+		if err != nil {
+			return "", nil, err
+		}
+		return "", cardImage, nil
+	}
+
+	result, err := t.api.GetRandomQuoteWithImage(t.context(context.Background()), req)
 	if err != nil {
 		return "", nil, err
 	}
 
-	return randomQuote, randomImage, nil
+	return result.Quote, result.Image, nil
+}
+
+// GenerateBatch fetches -count pairs in a single batch and displays each of them,
+// or saves them as numbered quote card PNG files when -output-dir is set.
+func (t *TerminalApp) GenerateBatch() error {
+	if t.outputDir != "" {
+		if err := os.MkdirAll(t.outputDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(t.context(context.Background()), BatchTimeout)
+	defer cancel()
+
+	req, err := t.options.PairRequest()
+	if err != nil {
+		return err
+	}
+	pairs, err := t.api.GetRandomQuotesWithImages(ctx, t.count, req)
+	if err != nil {
+		return err
+	}
+
+	var failures []error
+	for i, pair := range pairs {
+		if pair.Err != nil {
+			t.logger.ErrorContext(ctx, "Failed to fetch pair", "pair", i+1, logging.Err(pair.Err))
+			failures = append(failures, pair.Err)
+			continue
+		}
+
+		quote, img := pair.Quote, pair.Image
+		if t.options.Card != nil {
+			img, err = card.Render(pair.Image, pair.Quote, pair.Author, t.options.Card)
+			if err != nil {
+				return fmt.Errorf("failed to render quote card: %w", err)
+			}
+			quote = ""
+		}
+
+		if t.outputDir != "" {
+			path := filepath.Join(t.outputDir, fmt.Sprintf("card-%03d.png", i+1))
+			if err := gg.SavePNG(path, img); err != nil {
+				return fmt.Errorf("failed to save quote card: %w", err)
+			}
+			t.logger.InfoContext(ctx, "Saved quote card", "path", path)
+			continue
+		}
+		if err := t.DisplayContent(quote, img); err != nil {
+			return err
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to fetch %d of %d quote and image pairs: %w", len(failures), len(pairs), errors.Join(failures...))
+	}
+	return nil
+}
+
+// Slideshow displays a new quote and image every -interval until ctx is cancelled.
+// A pair that cannot be fetched before any other succeeded is logged and skipped.
+// When the terminal is resized, the current pair is drawn again to fit it.
+func (t *TerminalApp) Slideshow(ctx context.Context) error {
+	req, err := t.options.PairRequest()
+	if err != nil {
+		return err
+	}
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	pairs := t.api.Subscribe(ctx, t.interval, req)
+	var quote string
+	var img image.Image
+	for {
+		select {
+		case pair, ok := <-pairs:
+			if !ok {
+				return nil
+			}
+			if pair.Err != nil {
+				t.logger.ErrorContext(ctx, "Failed to fetch slideshow pair", logging.Err(pair.Err))
+				continue
+			}
+
+			quote, img = pair.Quote, pair.Image
+			if t.options.Card != nil {
+				img, err = card.Render(pair.Image, pair.Quote, pair.Author, t.options.Card)
+				if err != nil {
+					return fmt.Errorf("failed to render quote card: %w", err)
+				}
+				quote = ""
+			}
+			t.clearScreen()
+			if err := t.DisplayContent(quote, img); err != nil {
+				return err
+			}
+
+		case <-resized:
+			if img == nil {
+				continue
+			}
+			t.logger.DebugContext(ctx, "Redrawing slideshow pair after terminal resize")
+			t.clearScreen()
+			if err := t.display(quote, img); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// clearScreen clears the terminal between slideshow pairs; other output is left alone.
+func (t *TerminalApp) clearScreen() {
+	if isTerminal(t.out) {
+		fmt.Fprint(t.out, clearScreen)
+	}
 }
 
 // DisplayContent displays the quote and image content for the terminal application.
+// A quote card has the quote drawn into the image, so only the image is displayed.
 func (t *TerminalApp) DisplayContent(quote string, img image.Image) error {
-	displayRandomQuote(quote)
-	return displayImageInTerminal(img, t.options.ImageWidth, t.options.ImageHeight)
+	if t.outputPath != "" {
+		if err := gg.SavePNG(t.outputPath, img); err != nil {
+			return fmt.Errorf("failed to save quote card: %w", err)
+		}
+		t.logger.Info("Saved quote card", logging.RequestIDKey, t.requestID, "path", t.outputPath)
+	}
+
+	return t.display(quote, img)
+}
+
+// display writes the quote and draws the image below it, in the cells chosen by imageCells.
+func (t *TerminalApp) display(quote string, img image.Image) error {
+	if quote != "" {
+		fmt.Fprintln(t.out, quote)
+	}
+	columns, rows := t.imageCells(quote, img)
+	return t.renderer.Render(t.out, img, columns, rows)
 }
 
-// displayRandomQuote displays the random quote in the terminal.
-func displayRandomQuote(quote string) {
-	fmt.Println(quote)
+// imageCells returns the character cells the image is drawn in: at most 2*width columns and
+// height rows of the requested image size, shrunk to fit the terminal below the quote and a
+// prompt line, with the aspect ratio of the image.
+func (t *TerminalApp) imageCells(quote string, img image.Image) (int, int) {
+	maxColumns, maxRows := 2*t.options.ImageWidth, t.options.ImageHeight
+	cellAspect := defaultCellAspect
+	if size, ok := t.terminalSize(t.out); ok {
+		maxColumns = min(maxColumns, size.columns)
+		maxRows = min(maxRows, size.rows-quoteLines(quote, size.columns)-1)
+		cellAspect = size.cellAspect()
+	}
+	return fitCells(img.Bounds().Dx(), img.Bounds().Dy(), maxColumns, maxRows, cellAspect)
 }
 
-// displayImageInTerminal displays the image in the terminal using ASCII art.
-func displayImageInTerminal(img image.Image, width, height int) error {
-	dc := gg.NewContext(width, height)
-	dc.DrawImage(img, 0, 0)
+// ListCatalog prints one page of catalog images, one per line.
+// Use -image-id with a listed ID to preview the image together with a quote.
+func (t *TerminalApp) ListCatalog() error {
+	if t.catalog == nil {
+		return fmt.Errorf("image catalog is not available for this image provider")
+	}
 
-	for y := 0; y < height; y += 1 {
-		for x := 0; x < width; x += 1 {
-			r, g, b, _ := dc.Image().At(x, y).RGBA()
-			fmt.Printf("\x1b[48;2;%d;%d;%dm  \x1b[0m", r>>8, g>>8, b>>8)
-		}
-		fmt.Println()
+	images, err := t.catalog.ListImages(t.context(context.Background()), t.catalogQuery)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range images {
+		fmt.Fprintln(t.out, info)
 	}
 	return nil
 }
@@ -1,12 +1,21 @@
 package terminal
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"image"
+	"net/http"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/ramyad/tucows/internal/api/facade"
 	"github.com/ramyad/tucows/internal/api/imageapi"
-	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/card"
+	"github.com/ramyad/tucows/internal/shared"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -15,25 +24,215 @@ type MockAPIFacade struct {
 	mock.Mock
 }
 
-func (m *MockAPIFacade) GetRandomQuoteWithImage(qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) (string, image.Image, error) {
-	args := m.Called(qtcnfbldr, imgCnfgBldr)
-	return args.String(0), args.Get(1).(image.Image), args.Error(2)
+func (m *MockAPIFacade) GetRandomQuoteWithImage(ctx context.Context, req api.PairRequest) (api.PairResult, error) {
+	args := m.Called(req)
+	return args.Get(0).(api.PairResult), args.Error(1)
+}
+
+func (m *MockAPIFacade) GetRandomQuotesWithImages(ctx context.Context, n int, req api.PairRequest) ([]api.Pair, error) {
+	args := m.Called(n, req)
+	return args.Get(0).([]api.Pair), args.Error(1)
+}
+
+func (m *MockAPIFacade) Subscribe(ctx context.Context, interval time.Duration, req api.PairRequest) <-chan api.Pair {
+	args := m.Called(interval, req)
+	return args.Get(0).(<-chan api.Pair)
+}
+
+func (m *MockAPIFacade) GetQuoteCard(ctx context.Context, req api.PairRequest, cardCnfgBldr *card.CardConfigBuilder) (image.Image, error) {
+	args := m.Called(req, cardCnfgBldr)
+	return args.Get(0).(image.Image), args.Error(1)
 }
 
 func TestRun_success(t *testing.T) {
 	mockAPI := new(MockAPIFacade)
-	app := NewTerminalApp(mockAPI)
-	mockAPI.On("GetRandomQuoteWithImage", mock.Anything, mock.Anything).Return("Random Quote", image.NewRGBA(image.Rect(0, 0, 1, 1)), nil)
+	app := NewTerminalApp(mockAPI, WithArgs(nil))
+	mockAPI.On("GetRandomQuoteWithImage", mock.Anything).Return(api.PairResult{Quote: "Random Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}, nil)
 	err := app.Run()
 	assert.Nil(t, err, "Expected no error")
 
 }
 
+func TestRun_ColorMode(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	mockAPI.On("GetRandomQuoteWithImage", mock.Anything).Return(api.PairResult{Quote: "Random Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}, nil)
+
+	var out bytes.Buffer
+	err := NewTerminalApp(mockAPI, WithArgs([]string{"-width", "2", "-height", "1"}), WithOutput(&out)).Run()
+	assert.NoError(t, err)
+	assert.Equal(t, "Random Quote\n  \n", out.String(), "Expected no escape sequences when not writing to a terminal")
+
+	out.Reset()
+	err = NewTerminalApp(mockAPI, WithArgs([]string{"-width", "1", "-height", "1", "-color", "256", "-render", "blocks"}), WithOutput(&out)).Run()
+	assert.NoError(t, err)
+	assert.Equal(t, "Random Quote\n\x1b[48;5;16m  \x1b[0m\n", out.String())
+}
+
 func TestRun_FetchQuoteAndImageError(t *testing.T) {
 	mockAPI := new(MockAPIFacade)
-	app := NewTerminalApp(mockAPI)
-	mockAPI.On("GetRandomQuoteWithImage", mock.Anything, mock.Anything).Return("", image.NewRGBA(image.Rect(0, 0, 0, 0)), errors.New("Failed to fetch random quote image"))
+	app := NewTerminalApp(mockAPI, WithArgs(nil))
+	mockAPI.On("GetRandomQuoteWithImage", mock.Anything).Return(api.PairResult{Quote: "", Image: image.NewRGBA(image.Rect(0, 0, 0, 0))}, errors.New("Failed to fetch random quote image"))
 	err := app.Run()
 	assert.Error(t, err, "Expected error as GetRandomQuoteWithImage returned error")
 	assert.EqualError(t, err, "Failed to fetch random quote image")
 }
+
+type MockImageCatalog struct {
+	mock.Mock
+}
+
+func (m *MockImageCatalog) ListImages(ctx context.Context, query imageapi.CatalogQuery) ([]imageapi.ImageInfo, error) {
+	args := m.Called(query)
+	return args.Get(0).([]imageapi.ImageInfo), args.Error(1)
+}
+
+func (m *MockImageCatalog) GetImageInfo(ctx context.Context, id string) (imageapi.ImageInfo, error) {
+	args := m.Called(id)
+	return args.Get(0).(imageapi.ImageInfo), args.Error(1)
+}
+
+func TestRun_ListCatalog(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	mockCatalog := new(MockImageCatalog)
+	mockCatalog.On("ListImages", imageapi.CatalogQuery{Page: 3, Limit: 5, Author: "Jarvis"}).
+		Return([]imageapi.ImageInfo{{ID: "1", Author: "Paul Jarvis"}}, nil)
+	app := NewTerminalApp(mockAPI, WithCatalog(mockCatalog), WithArgs([]string{"-list", "-page", "3", "-limit", "5", "-author", "Jarvis"}))
+	err := app.Run()
+	assert.Nil(t, err, "Expected no error")
+	mockCatalog.AssertExpectations(t)
+	mockAPI.AssertNotCalled(t, "GetRandomQuoteWithImage", mock.Anything)
+}
+
+func TestRun_ListCatalogWithoutCatalog(t *testing.T) {
+	app := NewTerminalApp(new(MockAPIFacade), WithArgs([]string{"-list"}))
+	err := app.Run()
+	assert.Error(t, err, "Expected error as no catalog is configured")
+}
+
+func TestParseRequest_ImageID(t *testing.T) {
+	app := NewTerminalApp(new(MockAPIFacade), WithArgs([]string{"-image-id", "237", "-filters", "blur"}))
+	err := app.ParseRequest()
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, "237", app.(*TerminalApp).options.ImageID)
+	assert.Equal(t, []string{"blur"}, app.(*TerminalApp).options.Filters)
+}
+
+func TestRun_QuoteCard(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	outputPath := filepath.Join(t.TempDir(), "card.png")
+	app := NewTerminalApp(mockAPI, WithArgs([]string{"-card", "-align", "left", "-output", outputPath}))
+	mockAPI.On("GetQuoteCard", mock.Anything, mock.Anything).Return(image.NewRGBA(image.Rect(0, 0, 2, 2)), nil)
+	err := app.Run()
+	assert.Nil(t, err, "Expected no error")
+	mockAPI.AssertNotCalled(t, "GetRandomQuoteWithImage", mock.Anything)
+	assert.FileExists(t, outputPath, "Expected the quote card to be saved")
+}
+
+func TestParseRequest_InvalidCardAlignment(t *testing.T) {
+	app := NewTerminalApp(new(MockAPIFacade), WithArgs([]string{"-card", "-align", "diagonal"}))
+	err := app.ParseRequest()
+	assert.Error(t, err, "Expected error for invalid alignment")
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig([]string{"-quote-provider", "builtin", "-image-provider", "dir:/tmp/images", "-width", "20"})
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, "builtin", cfg.Quote.Provider)
+	assert.Equal(t, "dir:/tmp/images", cfg.Image.Provider)
+	assert.Equal(t, 20, cfg.Image.Width)
+	assert.Equal(t, DefaultImageHeight, cfg.Image.Height)
+
+	cfg, err = LoadConfig(nil)
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, "forismatic", cfg.Quote.Provider)
+	assert.Equal(t, "picsum", cfg.Image.Provider)
+
+	cfg, err = LoadConfig([]string{"-profile", "dev", "-image-provider", "picsum"})
+	assert.Nil(t, err, "Expected no error")
+	assert.Equal(t, "builtin", cfg.Quote.Provider, "Expected the profile value")
+	assert.Equal(t, "picsum", cfg.Image.Provider, "Expected flags to override the profile")
+
+	cfg, err = LoadConfig([]string{"-list", "-page", "2", "-output", "card.png", "-margin", "8"})
+	assert.Nil(t, err, "Expected the terminal-only flags to be accepted")
+	assert.Equal(t, 8, cfg.Card.Margin)
+
+	_, err = LoadConfig([]string{"-width", "wide"})
+	assert.Equal(t, ExitInvalidInput, ExitCode(err))
+}
+
+func TestRun_BatchSavesQuoteCards(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	outputDir := filepath.Join(t.TempDir(), "cards")
+	pairs := []api.Pair{
+		{PairResult: api.PairResult{Quote: "First Quote", Author: "Someone", Image: image.NewRGBA(image.Rect(0, 0, 40, 30))}},
+		{PairResult: api.PairResult{Quote: "Second Quote", Image: image.NewRGBA(image.Rect(0, 0, 40, 30))}},
+	}
+	mockAPI.On("GetRandomQuotesWithImages", 2, mock.Anything).Return(pairs, nil)
+	app := NewTerminalApp(mockAPI, WithArgs([]string{"-count", "2", "-output-dir", outputDir}))
+	err := app.Run()
+	assert.Nil(t, err, "Expected no error")
+	assert.FileExists(t, filepath.Join(outputDir, "card-001.png"))
+	assert.FileExists(t, filepath.Join(outputDir, "card-002.png"))
+}
+
+func TestRun_BatchReportsFailedPairs(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	pairs := []api.Pair{
+		{PairResult: api.PairResult{Quote: "First Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}},
+		{Err: errors.New("fetch failed")},
+	}
+	mockAPI.On("GetRandomQuotesWithImages", 2, mock.Anything).Return(pairs, nil)
+	app := NewTerminalApp(mockAPI, WithArgs([]string{"-count", "2"}))
+	err := app.Run()
+	assert.EqualError(t, err, "failed to fetch 1 of 2 quote and image pairs: fetch failed")
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, ExitOK},
+		{"unclassified", errors.New("unexpected"), ExitFailure},
+		{"upstream unavailable", &facade.QuoteFetchError{Err: shared.StatusError(http.StatusServiceUnavailable)}, ExitUpstreamUnavailable},
+		{"upstream bad response", shared.StatusError(http.StatusNotFound), ExitUpstreamBadResponse},
+		{"decode failure", shared.Wrap(shared.ErrDecode, errors.New("bad JSON")), ExitDecodeFailure},
+		{"timeout", fmt.Errorf("batch: %w", context.DeadlineExceeded), ExitTimeout},
+		{"rate limited", shared.StatusError(http.StatusTooManyRequests), ExitRateLimited},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExitCode(tt.err))
+		})
+	}
+
+	err := NewTerminalApp(new(MockAPIFacade), WithArgs([]string{"-width", "wide"})).Run()
+	assert.Equal(t, ExitInvalidInput, ExitCode(err))
+
+	mockAPI := new(MockAPIFacade)
+	mockAPI.On("GetRandomQuotesWithImages", 2, mock.Anything).Return([]api.Pair{
+		{Err: &facade.ImageFetchError{Err: shared.StatusError(http.StatusBadGateway)}},
+		{Err: &facade.ImageFetchError{Err: shared.StatusError(http.StatusBadGateway)}},
+	}, nil)
+	err = NewTerminalApp(mockAPI, WithArgs([]string{"-count", "2"})).Run()
+	assert.Equal(t, ExitUpstreamUnavailable, ExitCode(err))
+}
+
+func TestSlideshow_DisplaysPairsUntilClosed(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	outputPath := filepath.Join(t.TempDir(), "slide.png")
+	pairs := make(chan api.Pair, 3)
+	pairs <- api.Pair{Err: errors.New("fetch failed")}
+	pairs <- api.Pair{PairResult: api.PairResult{Quote: "First Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}}
+	pairs <- api.Pair{PairResult: api.PairResult{Quote: "Second Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}}
+	close(pairs)
+	mockAPI.On("Subscribe", 10*time.Second, mock.Anything).Return((<-chan api.Pair)(pairs))
+
+	app := NewTerminalApp(mockAPI, WithArgs([]string{"-interval", "10s", "-output", outputPath})).(*TerminalApp)
+	assert.NoError(t, app.ParseRequest())
+	err := app.Slideshow(context.Background())
+	assert.Nil(t, err, "Expected no error")
+	assert.FileExists(t, outputPath)
+	mockAPI.AssertExpectations(t)
+}
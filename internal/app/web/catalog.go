@@ -0,0 +1,92 @@
+package web
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/logging"
+	"github.com/ramyad/tucows/internal/shared"
+	"github.com/ramyad/tucows/internal/static"
+)
+
+// CatalogData represents the data passed to the catalog template.
+type CatalogData struct {
+	Images   []imageapi.ImageInfo
+	Author   string
+	Page     int
+	PrevPage int
+	NextPage int
+	Limit    int
+}
+
+// HandleCatalog handles the HTTP request for browsing the image catalog.
+// Each listed image links back to "/" with an image_id parameter that pins it for the quote.
+func (w *WebApp) HandleCatalog(responseWriter http.ResponseWriter, request *http.Request) {
+	ctx := request.Context()
+	w.logger().InfoContext(ctx, "Handling image catalog request")
+
+	query, err := parseCatalogQuery(request)
+	if err != nil {
+		w.logger().WarnContext(ctx, "Invalid catalog parameters", logging.Err(err))
+		writeError(responseWriter, http.StatusBadRequest, "Invalid request parameters", err)
+		return
+	}
+
+	images, err := w.Catalog.ListImages(ctx, query)
+	if err != nil {
+		w.logger().ErrorContext(ctx, "Failed to list image catalog", logging.Err(err))
+		writeError(responseWriter, statusForError(err), "Failed to fetch data", err)
+		return
+	}
+
+	data := CatalogData{
+		Images:   images,
+		Author:   query.Author,
+		Page:     query.Page,
+		PrevPage: query.Page - 1,
+		NextPage: query.Page + 1,
+		Limit:    query.Limit,
+	}
+	if err := executeCatalogTemplate(responseWriter, data); err != nil {
+		w.logger().ErrorContext(ctx, "Failed to execute catalog template", logging.Err(err))
+		writeError(responseWriter, http.StatusInternalServerError, "Failed to display data", err)
+	}
+}
+
+// parseCatalogQuery parses the page, limit and author query parameters.
+func parseCatalogQuery(request *http.Request) (imageapi.CatalogQuery, error) {
+	queryParams := request.URL.Query()
+	query := imageapi.CatalogQuery{
+		Page:   imageapi.DefaultCatalogPage,
+		Limit:  imageapi.DefaultCatalogLimit,
+		Author: queryParams.Get("author"),
+	}
+
+	if pageParam := queryParams.Get("page"); len(pageParam) > 0 {
+		page, err := strconv.Atoi(pageParam)
+		if err != nil || page < 1 {
+			return query, shared.InvalidInput("invalid value for page parameter: %q", pageParam)
+		}
+		query.Page = page
+	}
+
+	if limitParam := queryParams.Get("limit"); len(limitParam) > 0 {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 {
+			return query, shared.InvalidInput("invalid value for limit parameter: %q", limitParam)
+		}
+		query.Limit = min(limit, imageapi.MaxCatalogLimit)
+	}
+
+	return query, nil
+}
+
+func executeCatalogTemplate(w http.ResponseWriter, data CatalogData) error {
+	template, err := template.New("catalogTemplate").Parse(static.CatalogTemplate)
+	if err != nil {
+		return err
+	}
+	return template.Execute(w, data)
+}
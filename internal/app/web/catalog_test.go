@@ -0,0 +1,64 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockImageCatalog struct {
+	mock.Mock
+}
+
+func (m *MockImageCatalog) ListImages(ctx context.Context, query imageapi.CatalogQuery) ([]imageapi.ImageInfo, error) {
+	args := m.Called(query)
+	return args.Get(0).([]imageapi.ImageInfo), args.Error(1)
+}
+
+func (m *MockImageCatalog) GetImageInfo(ctx context.Context, id string) (imageapi.ImageInfo, error) {
+	args := m.Called(id)
+	return args.Get(0).(imageapi.ImageInfo), args.Error(1)
+}
+
+func TestHandleCatalog_Success(t *testing.T) {
+	mockCatalog := new(MockImageCatalog)
+	mockCatalog.On("ListImages", imageapi.CatalogQuery{Page: 2, Limit: 10, Author: "Jarvis"}).
+		Return([]imageapi.ImageInfo{{ID: "7", Author: "Paul Jarvis", Width: 100, Height: 50}}, nil)
+	app := &WebApp{
+		Catalog: mockCatalog,
+	}
+	req := httptest.NewRequest("GET", "/catalog?page=2&limit=10&author=Jarvis", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleCatalog(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code, "Expected status OK (200)")
+	assert.Contains(t, recorder.Body.String(), "/?image_id=7", "Catalog should link to pin the image")
+	assert.Contains(t, recorder.Body.String(), "Paul Jarvis")
+}
+
+func TestHandleCatalog_InvalidPage(t *testing.T) {
+	app := &WebApp{
+		Catalog: new(MockImageCatalog),
+	}
+	req := httptest.NewRequest("GET", "/catalog?page=abc", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleCatalog(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "Expected error 400")
+}
+
+func TestHandleCatalog_ListError(t *testing.T) {
+	mockCatalog := new(MockImageCatalog)
+	mockCatalog.On("ListImages", mock.Anything).Return([]imageapi.ImageInfo(nil), errors.New("catalog unavailable"))
+	app := &WebApp{
+		Catalog: mockCatalog,
+	}
+	req := httptest.NewRequest("GET", "/catalog", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleCatalog(recorder, req)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code, "Expected error 500")
+}
@@ -0,0 +1,68 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ramyad/tucows/internal/api/facade"
+	"github.com/ramyad/tucows/internal/shared"
+)
+
+// ErrorResponse is the JSON body of an error response.
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes an error. Code is the kind of the error, e.g. "upstream_unavailable",
+// or "internal" when it has none.
+type ErrorDetail struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// writeError replies with status and a JSON body describing err with message.
+// The error itself is logged rather than returned, as it may reveal upstream details.
+func writeError(responseWriter http.ResponseWriter, status int, message string, err error) {
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.Header().Set("X-Content-Type-Options", "nosniff")
+	responseWriter.WriteHeader(status)
+	_ = json.NewEncoder(responseWriter).Encode(ErrorResponse{Error: ErrorDetail{
+		Code:      shared.Code(err),
+		Message:   message,
+		RequestID: responseWriter.Header().Get(RequestIDHeader),
+	}})
+}
+
+// statusForError maps an error to an HTTP status code by its kind: 400 for invalid input,
+// 429 when rate limited, 504 on timeouts, 503 when an upstream API is unavailable and 502
+// for bad or undecodable upstream responses. Fetch errors without a kind map to 503 when
+// both upstream APIs failed and 502 when one of them failed; anything else maps to 500.
+func statusForError(err error) int {
+	switch shared.Kind(err) {
+	case shared.ErrInvalidInput:
+		return http.StatusBadRequest
+	case shared.ErrRateLimited:
+		return http.StatusTooManyRequests
+	case shared.ErrTimeout:
+		return http.StatusGatewayTimeout
+	case shared.ErrUpstreamUnavailable:
+		return http.StatusServiceUnavailable
+	case shared.ErrUpstreamBadResponse, shared.ErrDecode:
+		return http.StatusBadGateway
+	}
+
+	var quoteErr *facade.QuoteFetchError
+	var imageErr *facade.ImageFetchError
+	quoteFailed := errors.As(err, &quoteErr)
+	imageFailed := errors.As(err, &imageErr)
+	switch {
+	case quoteFailed && imageFailed:
+		return http.StatusServiceUnavailable
+	case quoteFailed || imageFailed:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
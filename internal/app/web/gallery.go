@@ -0,0 +1,131 @@
+package web
+
+import (
+	"context"
+	"html/template"
+	"image"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/ramyad/tucows/internal/app"
+	"github.com/ramyad/tucows/internal/card"
+	"github.com/ramyad/tucows/internal/logging"
+	"github.com/ramyad/tucows/internal/shared"
+	"github.com/ramyad/tucows/internal/static"
+)
+
+const (
+	DefaultGallerySize = 6
+	MaxGallerySize     = 24
+	// GalleryTimeout is the deadline shared by all pairs of a gallery.
+	GalleryTimeout = 20 * time.Second
+)
+
+// GalleryItem represents a single quote and image in the gallery template and live feed events.
+type GalleryItem struct {
+	Text   string       `json:"text,omitempty"`
+	Author string       `json:"author,omitempty"`
+	Image  template.URL `json:"image,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// GalleryData represents the data passed to the gallery template.
+type GalleryData struct {
+	Items []GalleryItem
+}
+
+// HandleGallery handles the HTTP request for a gallery of n quote/image pairs.
+// It accepts the same parameters as the random image and quote page plus n.
+func (w *WebApp) HandleGallery(responseWriter http.ResponseWriter, request *http.Request) {
+	w.logger().InfoContext(request.Context(), "Handling gallery request")
+
+	n, err := parseGallerySize(request)
+	var options app.Options
+	if err == nil {
+		options, err = w.parseOptions(request)
+	}
+	if err != nil {
+		w.logger().WarnContext(request.Context(), "Invalid request parameters", logging.Err(err))
+		writeError(responseWriter, http.StatusBadRequest, "Invalid request parameters", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(request.Context(), GalleryTimeout)
+	defer cancel()
+
+	// A fixed image ID would defeat the gallery's de-duplication, so it is ignored.
+	options.ImageID = ""
+	req, err := options.PairRequest()
+	if err != nil {
+		w.logger().WarnContext(ctx, "Invalid request parameters", logging.Err(err))
+		writeError(responseWriter, http.StatusBadRequest, "Invalid request parameters", err)
+		return
+	}
+
+	pairs, err := w.API.GetRandomQuotesWithImages(ctx, n, req)
+	if err != nil {
+		w.logger().ErrorContext(ctx, "Failed to fetch gallery", logging.Err(err))
+		writeError(responseWriter, statusForError(err), "Failed to fetch data", err)
+		return
+	}
+
+	data := GalleryData{Items: make([]GalleryItem, 0, len(pairs))}
+	for _, pair := range pairs {
+		data.Items = append(data.Items, w.galleryItem(ctx, pair, options.Card))
+	}
+
+	if err := executeGalleryTemplate(responseWriter, data); err != nil {
+		w.logger().ErrorContext(ctx, "Failed to execute gallery template", logging.Err(err))
+		writeError(responseWriter, http.StatusInternalServerError, "Failed to display data", err)
+	}
+}
+
+// galleryItem converts a pair into a gallery item, rendering it as a quote card when cardConfig is set.
+func (w *WebApp) galleryItem(ctx context.Context, pair api.Pair, cardConfig *card.CardConfigBuilder) GalleryItem {
+	if pair.Err != nil {
+		w.logger().ErrorContext(ctx, "Failed to fetch gallery pair", logging.Err(pair.Err))
+		return GalleryItem{Error: "Failed to fetch data"}
+	}
+
+	item := GalleryItem{Text: pair.Quote, Author: pair.Author}
+	var img image.Image = pair.Image
+	if cardConfig != nil {
+		cardImage, err := card.Render(pair.Image, pair.Quote, pair.Author, cardConfig)
+		if err != nil {
+			w.logger().ErrorContext(ctx, "Failed to render quote card", logging.Err(err))
+			return GalleryItem{Error: "Failed to display data"}
+		}
+		img, item = cardImage, GalleryItem{}
+	}
+
+	encoded, err := encodeImageToBase64(img)
+	if err != nil {
+		w.logger().ErrorContext(ctx, "Failed to encode image to base64", logging.Err(err))
+		return GalleryItem{Error: "Failed to display data"}
+	}
+	item.Image = template.URL("data:image/jpeg;base64," + encoded)
+	return item
+}
+
+// parseGallerySize parses the n query parameter.
+func parseGallerySize(request *http.Request) (int, error) {
+	nParam := request.URL.Query().Get("n")
+	if len(nParam) == 0 {
+		return DefaultGallerySize, nil
+	}
+	n, err := strconv.Atoi(nParam)
+	if err != nil || n < 1 || n > MaxGallerySize {
+		return 0, shared.InvalidInput("invalid value for n parameter: %q", nParam)
+	}
+	return n, nil
+}
+
+func executeGalleryTemplate(w http.ResponseWriter, data GalleryData) error {
+	template, err := template.New("galleryTemplate").Parse(static.GalleryTemplate)
+	if err != nil {
+		return err
+	}
+	return template.Execute(w, data)
+}
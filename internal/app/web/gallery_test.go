@@ -0,0 +1,78 @@
+package web
+
+import (
+	"errors"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleGallery_Success(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	pairs := []api.Pair{
+		{PairResult: api.PairResult{Quote: "First <Quote>", Author: "Someone", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}},
+		{Err: errors.New("fetch failed")},
+		{PairResult: api.PairResult{Quote: "Third Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}},
+	}
+	mockAPI.On("GetRandomQuotesWithImages", 3, mock.Anything).Return(pairs, nil)
+	app := &WebApp{
+		API: mockAPI,
+	}
+	req := httptest.NewRequest("GET", "/gallery?n=3&width=100", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleGallery(recorder, req)
+
+	body := recorder.Body.String()
+	assert.Equal(t, http.StatusOK, recorder.Code, "Expected status OK (200)")
+	assert.Equal(t, 2, strings.Count(body, "data:image/jpeg;base64,"), "Expected an image per successful pair")
+	assert.Contains(t, body, "First &lt;Quote&gt;", "Quotes should be escaped")
+	assert.Contains(t, body, "Failed to fetch data", "Failed pairs should be reported")
+}
+
+func TestHandleGallery_InvalidSize(t *testing.T) {
+	app := &WebApp{
+		API: new(MockAPIFacade),
+	}
+	req := httptest.NewRequest("GET", "/gallery?n=1000", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleGallery(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "Expected error 400")
+}
+
+func TestHandleGallery_ConcurrentRequestsKeepTheirOptions(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	pairs := []api.Pair{{PairResult: api.PairResult{Quote: "Plain Quote", Image: image.NewRGBA(image.Rect(0, 0, 10, 10))}}}
+	// Both requests are parsed before either renders its pairs.
+	var fetching sync.WaitGroup
+	fetching.Add(2)
+	mockAPI.On("GetRandomQuotesWithImages", 1, mock.Anything).Run(func(mock.Arguments) {
+		fetching.Done()
+		fetching.Wait()
+	}).Return(pairs, nil)
+	app := &WebApp{
+		API: mockAPI,
+	}
+
+	recorders := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+	var wg sync.WaitGroup
+	for i, target := range []string{"/gallery?n=1", "/gallery?n=1&card=true"} {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			app.HandleGallery(recorders[i], httptest.NewRequest("GET", target, nil))
+		}(i, target)
+	}
+	wg.Wait()
+
+	assert.Equal(t, http.StatusOK, recorders[0].Code)
+	assert.Equal(t, http.StatusOK, recorders[1].Code)
+	assert.Contains(t, recorders[0].Body.String(), "Plain Quote", "Expected the plain gallery to show the quote")
+	assert.NotContains(t, recorders[1].Body.String(), "Plain Quote", "Expected the card gallery to render the quote onto the image")
+}
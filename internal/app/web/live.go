@@ -0,0 +1,92 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ramyad/tucows/internal/app"
+	"github.com/ramyad/tucows/internal/logging"
+	"github.com/ramyad/tucows/internal/shared"
+	"github.com/ramyad/tucows/internal/static"
+)
+
+const (
+	DefaultLiveInterval = 30 * time.Second
+	// MinLiveInterval keeps live feed clients from hammering the upstream APIs.
+	MinLiveInterval = 5 * time.Second
+)
+
+// HandleLive serves the live feed page, which shows each pair streamed by /live/events.
+// The query parameters of the page are forwarded to the event stream.
+func (w *WebApp) HandleLive(responseWriter http.ResponseWriter, request *http.Request) {
+	w.logger().InfoContext(request.Context(), "Handling live feed page request")
+
+	responseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := fmt.Fprint(responseWriter, static.LiveTemplate); err != nil {
+		w.logger().ErrorContext(request.Context(), "Failed to write live feed page", logging.Err(err))
+	}
+}
+
+// HandleLiveEvents streams a new quote/image pair every interval as server-sent events
+// until the client disconnects. It accepts the same parameters as the random image and
+// quote page plus interval, a duration such as 30s.
+func (w *WebApp) HandleLiveEvents(responseWriter http.ResponseWriter, request *http.Request) {
+	ctx := request.Context()
+	w.logger().InfoContext(ctx, "Handling live feed events request")
+
+	interval, err := parseLiveInterval(request)
+	var options app.Options
+	if err == nil {
+		options, err = w.parseOptions(request)
+	}
+	if err != nil {
+		w.logger().WarnContext(ctx, "Invalid request parameters", logging.Err(err))
+		writeError(responseWriter, http.StatusBadRequest, "Invalid request parameters", err)
+		return
+	}
+
+	flusher, ok := responseWriter.(http.Flusher)
+	if !ok {
+		writeError(responseWriter, http.StatusInternalServerError, "Streaming is not supported", nil)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "text/event-stream")
+	responseWriter.Header().Set("Cache-Control", "no-cache")
+	responseWriter.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	req, err := options.PairRequest()
+	if err != nil {
+		w.logger().WarnContext(ctx, "Invalid live feed parameters", logging.Err(err))
+		return
+	}
+	for pair := range w.API.Subscribe(ctx, interval, req) {
+		event, err := json.Marshal(w.galleryItem(ctx, pair, options.Card))
+		if err != nil {
+			w.logger().ErrorContext(ctx, "Failed to encode live feed event", logging.Err(err))
+			continue
+		}
+		if _, err := fmt.Fprintf(responseWriter, "data: %s\n\n", event); err != nil {
+			w.logger().ErrorContext(ctx, "Failed to write live feed event", logging.Err(err))
+			return
+		}
+		flusher.Flush()
+	}
+	w.logger().InfoContext(ctx, "Live feed client disconnected")
+}
+
+// parseLiveInterval parses the interval query parameter.
+func parseLiveInterval(request *http.Request) (time.Duration, error) {
+	intervalParam := request.URL.Query().Get("interval")
+	if len(intervalParam) == 0 {
+		return DefaultLiveInterval, nil
+	}
+	interval, err := time.ParseDuration(intervalParam)
+	if err != nil || interval < MinLiveInterval {
+		return 0, shared.InvalidInput("invalid value for interval parameter: %q", intervalParam)
+	}
+	return interval, nil
+}
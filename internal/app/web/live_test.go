@@ -0,0 +1,78 @@
+package web
+
+import (
+	"errors"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleLiveEvents_StreamsPairs(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	pairs := make(chan api.Pair, 2)
+	pairs <- api.Pair{Err: errors.New("fetch failed")}
+	pairs <- api.Pair{PairResult: api.PairResult{Quote: "Live Quote", Author: "Someone", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}}
+	close(pairs)
+	mockAPI.On("Subscribe", 10*time.Second, mock.Anything).Return((<-chan api.Pair)(pairs))
+	app := &WebApp{
+		API: mockAPI,
+	}
+	req := httptest.NewRequest("GET", "/live/events?interval=10s", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleLiveEvents(recorder, req)
+
+	body := recorder.Body.String()
+	assert.Equal(t, http.StatusOK, recorder.Code, "Expected status OK (200)")
+	assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, 2, strings.Count(body, "data: "), "Expected an event per pair")
+	assert.Contains(t, body, `"error":"Failed to fetch data"`)
+	assert.Contains(t, body, `"text":"Live Quote","author":"Someone","image":"data:image/jpeg;base64,`)
+}
+
+func TestHandleLiveEvents_InvalidInterval(t *testing.T) {
+	app := &WebApp{
+		API: new(MockAPIFacade),
+	}
+	req := httptest.NewRequest("GET", "/live/events?interval=1s", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleLiveEvents(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "Expected error 400")
+}
+
+func TestHandleLiveEvents_ConcurrentRequestsKeepTheirOptions(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	cardPairs, plainPairs := make(chan api.Pair), make(chan api.Pair)
+	mockAPI.On("Subscribe", 10*time.Second, mock.Anything).Return((<-chan api.Pair)(cardPairs))
+	mockAPI.On("Subscribe", 20*time.Second, mock.Anything).Return((<-chan api.Pair)(plainPairs))
+	app := &WebApp{
+		API: mockAPI,
+	}
+
+	recorders := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+	var wg sync.WaitGroup
+	for i, target := range []string{"/live/events?interval=10s&card=true", "/live/events?interval=20s"} {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			app.HandleLiveEvents(recorders[i], httptest.NewRequest("GET", target, nil))
+		}(i, target)
+	}
+
+	// Both streams are open before either receives a pair.
+	cardPairs <- api.Pair{PairResult: api.PairResult{Quote: "Card Quote", Image: image.NewRGBA(image.Rect(0, 0, 10, 10))}}
+	plainPairs <- api.Pair{PairResult: api.PairResult{Quote: "Plain Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}}
+	close(cardPairs)
+	close(plainPairs)
+	wg.Wait()
+
+	assert.NotContains(t, recorders[0].Body.String(), "Card Quote", "Expected the card stream to render quote cards")
+	assert.Contains(t, recorders[1].Body.String(), `"text":"Plain Quote"`, "Expected the plain stream to show quotes")
+}
@@ -0,0 +1,39 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ramyad/tucows/internal/api/breaker"
+	"github.com/ramyad/tucows/internal/api/facade"
+	"github.com/ramyad/tucows/internal/logging"
+)
+
+// StatusData represents the JSON body of the /status page.
+type StatusData struct {
+	Breakers []breaker.Status   `json:"breakers"`
+	Hedges   *facade.HedgeStats `json:"hedges,omitempty"`
+}
+
+// hedgeReporter is implemented by APIs that hedge slow provider requests.
+type hedgeReporter interface {
+	HedgeStats() facade.HedgeStats
+}
+
+// HandleStatus reports the state of the provider circuit breakers and, when the API
+// hedges requests, the hedge counts as JSON.
+func (w *WebApp) HandleStatus(responseWriter http.ResponseWriter, request *http.Request) {
+	data := StatusData{Breakers: make([]breaker.Status, 0, len(w.Breakers))}
+	for _, b := range w.Breakers {
+		data.Breakers = append(data.Breakers, b.Status())
+	}
+	if reporter, ok := w.API.(hedgeReporter); ok {
+		hedges := reporter.HedgeStats()
+		data.Hedges = &hedges
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(responseWriter).Encode(data); err != nil {
+		w.logger().ErrorContext(request.Context(), "Failed to write status", logging.Err(err))
+	}
+}
@@ -0,0 +1,40 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api/breaker"
+	"github.com/ramyad/tucows/internal/api/facade"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleStatus_ReportsBreakers(t *testing.T) {
+	quoteBreaker := breaker.NewBreakerBuilder("quote").WithFailureThreshold(1).Build()
+	imageBreaker := breaker.NewBreakerBuilder("image").Build()
+	_ = quoteBreaker.Do(context.Background(), func() error { return errors.New("quote api down") })
+
+	app := NewWebApp(new(MockAPIFacade), 0, WithBreakers(quoteBreaker, imageBreaker)).(*WebApp)
+	req := httptest.NewRequest("GET", "/status", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleStatus(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Expected status OK (200)")
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `{"name":"quote","state":"open","consecutive_failures":1,`)
+	assert.Contains(t, recorder.Body.String(), `{"name":"image","state":"closed","consecutive_failures":0}`)
+}
+
+func TestHandleStatus_ReportsHedges(t *testing.T) {
+	app := NewWebApp(facade.NewAPIFacade(facade.WithImageHedgeDelay(time.Second)), 0).(*WebApp)
+	req := httptest.NewRequest("GET", "/status", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleStatus(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Expected status OK (200)")
+	assert.JSONEq(t, `{"breakers":[],"hedges":{"quote":{"hedged":0,"won":0},"image":{"hedged":0,"won":0}}}`, recorder.Body.String())
+}
@@ -6,17 +6,19 @@ import (
 	"fmt"
 	"image"
 	"image/jpeg"
-	"log"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"text/template"
-	"time"
 
 	"github.com/ramyad/tucows/internal/api"
+	"github.com/ramyad/tucows/internal/api/breaker"
 	"github.com/ramyad/tucows/internal/api/imageapi"
-	"github.com/ramyad/tucows/internal/api/quoteapi"
 	"github.com/ramyad/tucows/internal/app"
+	"github.com/ramyad/tucows/internal/card"
+	"github.com/ramyad/tucows/internal/logging"
 	"github.com/ramyad/tucows/internal/shared"
 	"github.com/ramyad/tucows/internal/static"
 )
@@ -25,6 +27,8 @@ const (
 	DefaultWebImageWidth  = 600
 	DefaultWebImageHeight = 400
 	DefaultTextCategory   = 0
+	// RequestIDHeader carries the request ID, taken from the request when the client sets it.
+	RequestIDHeader = "X-Request-ID"
 )
 
 // Data represents the data to be passed to the template.
@@ -41,17 +45,59 @@ type WebApp struct {
 	AppOptions      app.Options
 	RenderedContent Data
 	Port            int
+	Catalog         imageapi.ImageCatalog
+	Breakers        []*breaker.Breaker
+	// Defaults are the options used for query parameters that a request leaves out.
+	Defaults app.Options
+	// Logger defaults to slog.Default() when nil.
+	Logger *slog.Logger
 }
 
 // Ensure that *WebApp implements app.APP interface
 var _ app.App = (*WebApp)(nil)
 
+// Option configures optional behaviour of the WebApp.
+type Option func(*WebApp)
+
+// WithCatalog enables the /catalog page backed by the given image catalog.
+func WithCatalog(catalog imageapi.ImageCatalog) Option {
+	return func(w *WebApp) {
+		w.Catalog = catalog
+	}
+}
+
+// WithBreakers reports the state of the given provider circuit breakers on the /status page.
+func WithBreakers(breakers ...*breaker.Breaker) Option {
+	return func(w *WebApp) {
+		w.Breakers = append(w.Breakers, breakers...)
+	}
+}
+
+// WithDefaults sets the options used for query parameters that a request leaves out.
+func WithDefaults(defaults app.Options) Option {
+	return func(w *WebApp) {
+		w.Defaults = defaults
+	}
+}
+
+// WithLogger sets the logger of the WebApp.
+func WithLogger(logger *slog.Logger) Option {
+	return func(w *WebApp) {
+		w.Logger = logger
+	}
+}
+
 // NewTerminalApp creates a new instance of the TerminalApp.
-func NewWebApp(api api.API, port int) app.App {
-	return &WebApp{
-		API:  api,
-		Port: port,
+func NewWebApp(api api.API, port int, opts ...Option) app.App {
+	w := &WebApp{
+		API:      api,
+		Port:     port,
+		Defaults: *app.NewOptions(DefaultTextCategory, DefaultWebImageWidth, DefaultWebImageHeight, nil),
+	}
+	for _, opt := range opts {
+		opt(w)
 	}
+	return w
 }
 
 // Run starts the web application by handling the HTTP requests
@@ -59,113 +105,187 @@ func NewWebApp(api api.API, port int) app.App {
 func (w *WebApp) Run() error {
 	addr := fmt.Sprintf(":%d", w.Port)
 
-	log.Printf("[%s] [%s] Starting web application on %s ...", time.Now(), shared.LogLevelInfo, addr)
+	w.logger().Info("Starting web application", "addr", addr)
 
-	http.HandleFunc("/", w.HandleRandomImageQuote)
+	http.HandleFunc("/", WithRequestID(w.HandleRandomImageQuote))
+	http.HandleFunc("/gallery", WithRequestID(w.HandleGallery))
+	http.HandleFunc("/live", WithRequestID(w.HandleLive))
+	http.HandleFunc("/live/events", WithRequestID(w.HandleLiveEvents))
+	http.HandleFunc("/status", WithRequestID(w.HandleStatus))
+	if w.Catalog != nil {
+		http.HandleFunc("/catalog", WithRequestID(w.HandleCatalog))
+	}
 	err := http.ListenAndServe(addr, nil)
 	if err != nil {
-		log.Printf("[%s] Failed to start web application: %v", shared.LogLevelError, err)
+		w.logger().Error("Failed to start web application", logging.Err(err))
 		return err
 	}
 	return nil
 }
 
+// WithRequestID wraps handler so that the records logged while handling a request carry
+// its request ID. The ID is taken from the X-Request-ID header, or generated when the
+// client did not set one, and is echoed in the response.
+func WithRequestID(handler http.HandlerFunc) http.HandlerFunc {
+	return func(responseWriter http.ResponseWriter, request *http.Request) {
+		requestID := request.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = logging.NewRequestID()
+		}
+		responseWriter.Header().Set(RequestIDHeader, requestID)
+		ctx := logging.WithAttrs(request.Context(), logging.RequestIDKey, requestID)
+		handler(responseWriter, request.WithContext(ctx))
+	}
+}
+
+// logger returns the logger of the WebApp.
+func (w *WebApp) logger() *slog.Logger {
+	return logging.OrDefault(w.Logger)
+}
+
 // HandleRandomImageQuote handles the HTTP request for random image and quote.
 func (w *WebApp) HandleRandomImageQuote(responseWriter http.ResponseWriter, request *http.Request) {
-	log.Printf("[%s] [%s] Handling random image and quote request...", time.Now(), shared.LogLevelInfo)
+	ctx := request.Context()
+	w.logger().InfoContext(ctx, "Handling random image and quote request", "path", request.URL.Path)
 
 	w.IncomingRequest = request
 	w.ResponseWriter = responseWriter
 
 	err := w.ParseRequest()
 	if err != nil {
-		log.Printf("[%s] Invalid request parameteres: %v\n", shared.LogLevelError, err)
-		http.Error(w.ResponseWriter, "Invalid request parameters", http.StatusBadRequest)
+		w.logger().WarnContext(ctx, "Invalid request parameters", logging.Err(err))
+		writeError(w.ResponseWriter, http.StatusBadRequest, "Invalid request parameters", err)
 		return
 	}
 
 	quote, image, err := w.FetchQuoteAndImage()
 	if err != nil {
-		log.Printf("[%s] Failed to fetch data %v\n", shared.LogLevelError, err)
-		http.Error(w.ResponseWriter, "Failed to fetch data", http.StatusInternalServerError)
+		w.logger().ErrorContext(ctx, "Failed to fetch data", logging.Err(err))
+		writeError(w.ResponseWriter, statusForError(err), "Failed to fetch data", err)
 		return
 	}
 
 	err = w.DisplayContent(quote, image)
 	if err != nil {
-		log.Printf("[%s] Failed to display data %v\n", shared.LogLevelError, err)
-		http.Error(w.ResponseWriter, "Failed to display data", http.StatusInternalServerError)
+		w.logger().ErrorContext(ctx, "Failed to display data", logging.Err(err))
+		writeError(w.ResponseWriter, http.StatusInternalServerError, "Failed to display data", err)
 		return
 	}
 
-	log.Printf("[%s] [%s] Request handled successfully.", shared.LogLevelInfo, time.Now())
+	w.logger().InfoContext(ctx, "Request handled successfully")
 }
 
-// ParseRequest parses the web request and returns the Options.
+// ParseRequest parses the web request into the AppOptions.
 func (w *WebApp) ParseRequest() error {
-	queryParams := w.IncomingRequest.URL.Query()
-	w.AppOptions = *app.NewOptions(DefaultTextCategory, DefaultWebImageWidth, DefaultWebImageHeight, nil)
+	options, err := w.parseOptions(w.IncomingRequest)
+	if err != nil {
+		return err
+	}
+	w.AppOptions = options
+	return nil
+}
+
+// parseOptions parses the query parameters of request over the Defaults. It leaves the
+// WebApp untouched, so that concurrent requests can be parsed safely.
+func (w *WebApp) parseOptions(request *http.Request) (app.Options, error) {
+	queryParams := request.URL.Query()
+	options := w.Defaults
+	options.Filters = slices.Clone(w.Defaults.Filters)
+	// A WebApp created without NewWebApp has no defaults.
+	if options.ImageWidth == 0 && options.ImageHeight == 0 {
+		options.ImageWidth, options.ImageHeight = DefaultWebImageWidth, DefaultWebImageHeight
+	}
 
 	keyParam := queryParams.Get("key")
 	if len(keyParam) > 0 {
 		var err error
-		w.AppOptions.QuoteCategory, err = strconv.Atoi(keyParam)
+		options.QuoteCategory, err = strconv.Atoi(keyParam)
 		if err != nil {
-			log.Printf("[%s] Invalid value for key parameter: %v\n", shared.LogLevelError, err)
-			return fmt.Errorf("invalid value for key parameter: %s", err)
+			return app.Options{}, shared.InvalidInput("invalid value for key parameter: %w", err)
 		}
 	}
 
-	log.Printf("[%s] Quote configuration: key=%d\n", shared.LogLevelInfo, w.AppOptions.QuoteCategory)
-
 	widthParam := queryParams.Get("width")
 	if len(widthParam) > 0 {
 		var err error
-		w.AppOptions.ImageWidth, err = strconv.Atoi(widthParam)
+		options.ImageWidth, err = strconv.Atoi(widthParam)
 		if err != nil {
-			log.Printf("[%s] Invalid value for width parameter: %v\n", shared.LogLevelError, err)
-			return fmt.Errorf("invalid value for width parameter: %s", err)
+			return app.Options{}, shared.InvalidInput("invalid value for width parameter: %w", err)
 		}
 	}
 
 	heightParam := queryParams.Get("height")
 	if len(heightParam) > 0 {
 		var err error
-		w.AppOptions.ImageHeight, err = strconv.Atoi(heightParam)
+		options.ImageHeight, err = strconv.Atoi(heightParam)
 		if err != nil {
-			log.Printf("[%s] Invalid value for height parameter: %v\n", shared.LogLevelError, err)
-			return fmt.Errorf("invalid value for height parameter: %s", err)
+			return app.Options{}, shared.InvalidInput("invalid value for height parameter: %w", err)
 		}
 	}
 
-	filtersParam := queryParams.Get("filters")
-	w.AppOptions.Filters = imageapi.ImageFilters(strings.Split(filtersParam, ","))
+	if queryParams.Has("image_id") {
+		options.ImageID = queryParams.Get("image_id")
+	}
 
-	log.Printf("[%s] Image configuration: width=%d, height=%d, filters=%v\n", shared.LogLevelInfo, w.AppOptions.ImageWidth, w.AppOptions.ImageHeight, w.AppOptions.Filters)
+	cardParam := queryParams.Get("card")
+	if len(cardParam) > 0 {
+		renderCard, err := strconv.ParseBool(cardParam)
+		if err != nil {
+			return app.Options{}, shared.InvalidInput("invalid value for card parameter: %w", err)
+		}
+		options.Card = nil
+		if renderCard {
+			options.Card, err = card.ParseCardConfig(queryParams.Get("align"), queryParams.Get("valign"), queryParams.Get("background"), queryParams.Get("margin"))
+			if err != nil {
+				return app.Options{}, shared.InvalidInput("invalid quote card parameters: %w", err)
+			}
+		}
+	}
 
-	return nil
+	if queryParams.Has("filters") {
+		options.Filters = imageapi.ImageFilters(strings.Split(queryParams.Get("filters"), ","))
+	}
+
+	if _, err := options.PairRequest(); err != nil {
+		return app.Options{}, fmt.Errorf("invalid image parameters: %w", err)
+	}
+
+	w.logger().DebugContext(request.Context(), "Parsed request",
+		"key", options.QuoteCategory, "width", options.ImageWidth, "height", options.ImageHeight,
+		"filters", options.Filters, "image_id", options.ImageID, "card", options.Card != nil)
+
+	return options, nil
 }
 
 // FetchQuoteAndImage fetches a random quote and image for the terminal application.
 func (w *WebApp) FetchQuoteAndImage() (string, image.Image, error) {
-	quoteConfigBuilder := quoteapi.NewQuoteConfigBuilder().WithKey(w.AppOptions.QuoteCategory)
-	imageConfigBuilder := imageapi.NewImageConfigBuilder().WithWidth(w.AppOptions.ImageWidth).WithHeight(w.AppOptions.ImageHeight).WithFilters(w.AppOptions.Filters)
+	req, err := w.AppOptions.PairRequest()
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid request: %w", err)
+	}
+	ctx := w.IncomingRequest.Context()
+
+	if w.AppOptions.Card != nil {
+		cardImage, err := w.API.GetQuoteCard(ctx, req, w.AppOptions.Card)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get quote card: %w", err)
+		}
+		return "", cardImage, nil
+	}
 
-	randomQuote, randomImage, err := w.API.GetRandomQuoteWithImage(quoteConfigBuilder, imageConfigBuilder)
+	result, err := w.API.GetRandomQuoteWithImage(ctx, req)
 	if err != nil {
-		log.Printf("[%s] Failed to GetRandomQuoteWithImage: %v\n", shared.LogLevelError, err)
-		return "", nil, fmt.Errorf("failed to get random quote with image: %s", err)
+		return "", nil, fmt.Errorf("failed to get random quote with image: %w", err)
 	}
 
-	return randomQuote, randomImage, nil
+	return result.Quote, result.Image, nil
 }
 
 // DisplayContent displays the quote and image content for the web application.
 func (w *WebApp) DisplayContent(quote string, img image.Image) error {
 	image, err := encodeImageToBase64(img)
 	if err != nil {
-		log.Printf("[%s] Failed to encode image to base64: %v\n", shared.LogLevelError, err)
-		return fmt.Errorf("failed to encode image to base64: %v", err)
+		return fmt.Errorf("failed to encode image to base64: %w", err)
 	}
 
 	w.RenderedContent.Image = image
@@ -173,8 +293,7 @@ func (w *WebApp) DisplayContent(quote string, img image.Image) error {
 
 	err = executeTemplate(w.ResponseWriter, w.RenderedContent)
 	if err != nil {
-		log.Printf("[%s] Failed to execute template: %v\n", shared.LogLevelError, err)
-		return fmt.Errorf("failed to execute template: %v", err)
+		return fmt.Errorf("failed to execute template: %w", err)
 	}
 	return nil
 }
@@ -1,16 +1,24 @@
 package web
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"image"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/ramyad/tucows/internal/api/breaker"
 	"github.com/ramyad/tucows/internal/api/facade"
-	"github.com/ramyad/tucows/internal/api/imageapi"
-	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/app"
+	"github.com/ramyad/tucows/internal/card"
+	"github.com/ramyad/tucows/internal/logging"
+	"github.com/ramyad/tucows/internal/shared"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,15 +27,30 @@ type MockAPIFacade struct {
 	mock.Mock
 }
 
-func (m *MockAPIFacade) GetRandomQuoteWithImage(qtcnfbldr *quoteapi.QuoteConfigBuilder, imgCnfgBldr *imageapi.ImageConfigBuilder) (string, image.Image, error) {
-	args := m.Called(qtcnfbldr, imgCnfgBldr)
-	return args.String(0), args.Get(1).(image.Image), args.Error(2)
+func (m *MockAPIFacade) GetRandomQuoteWithImage(ctx context.Context, req api.PairRequest) (api.PairResult, error) {
+	args := m.Called(req)
+	return args.Get(0).(api.PairResult), args.Error(1)
+}
+
+func (m *MockAPIFacade) GetRandomQuotesWithImages(ctx context.Context, n int, req api.PairRequest) ([]api.Pair, error) {
+	args := m.Called(n, req)
+	return args.Get(0).([]api.Pair), args.Error(1)
+}
+
+func (m *MockAPIFacade) Subscribe(ctx context.Context, interval time.Duration, req api.PairRequest) <-chan api.Pair {
+	args := m.Called(interval, req)
+	return args.Get(0).(<-chan api.Pair)
+}
+
+func (m *MockAPIFacade) GetQuoteCard(ctx context.Context, req api.PairRequest, cardCnfgBldr *card.CardConfigBuilder) (image.Image, error) {
+	args := m.Called(req, cardCnfgBldr)
+	return args.Get(0).(image.Image), args.Error(1)
 }
 
 func TestHandleRandomImageQuote_Success(t *testing.T) {
 	mockAPI := new(MockAPIFacade)
-	mockAPI.On("GetRandomQuoteWithImage", mock.Anything, mock.Anything).
-		Return("Random Quote", image.NewRGBA(image.Rect(0, 0, 1, 1)), nil)
+	mockAPI.On("GetRandomQuoteWithImage", mock.Anything).
+		Return(api.PairResult{Quote: "Random Quote", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}, nil)
 	app := &WebApp{
 		API: mockAPI,
 	}
@@ -39,8 +62,8 @@ func TestHandleRandomImageQuote_Success(t *testing.T) {
 
 func TestHandleRandomImageQuote_FetchQuoteAndImageError(t *testing.T) {
 	mockAPI := new(MockAPIFacade)
-	mockAPI.On("GetRandomQuoteWithImage", mock.Anything, mock.Anything).
-		Return("", image.NewRGBA(image.Rect(0, 0, 1, 1)), errors.New("Failed to fetch random quote image"))
+	mockAPI.On("GetRandomQuoteWithImage", mock.Anything).
+		Return(api.PairResult{Quote: "", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}, errors.New("Failed to fetch random quote image"))
 	app := &WebApp{
 		API: mockAPI,
 	}
@@ -51,6 +74,103 @@ func TestHandleRandomImageQuote_FetchQuoteAndImageError(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "Failed to fetch data", "Error message should be in the response body")
 }
 
+func TestHandleRandomImageQuote_StatusByFetchError(t *testing.T) {
+	quoteErr := &facade.QuoteFetchError{Err: errors.New("quote api down")}
+	imageErr := &facade.ImageFetchError{Err: errors.New("image api down")}
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"quote failed", quoteErr, http.StatusBadGateway},
+		{"image failed", imageErr, http.StatusBadGateway},
+		{"both failed", errors.Join(quoteErr, imageErr), http.StatusServiceUnavailable},
+		{"breaker open", &facade.ImageFetchError{Err: &breaker.OpenError{Name: "image"}}, http.StatusServiceUnavailable},
+		{"other error", errors.New("unexpected"), http.StatusInternalServerError},
+		{"upstream unavailable", &facade.QuoteFetchError{Err: shared.StatusError(http.StatusServiceUnavailable)}, http.StatusServiceUnavailable},
+		{"upstream bad response", &facade.ImageFetchError{Err: shared.StatusError(http.StatusNotFound)}, http.StatusBadGateway},
+		{"decode failure", &facade.QuoteFetchError{Err: shared.Wrap(shared.ErrDecode, errors.New("bad JSON"))}, http.StatusBadGateway},
+		{"timeout", &facade.ImageFetchError{Err: context.DeadlineExceeded}, http.StatusGatewayTimeout},
+		{"rate limited", &facade.QuoteFetchError{Err: shared.StatusError(http.StatusTooManyRequests)}, http.StatusTooManyRequests},
+		{"invalid input", shared.InvalidInput("batch size must be between 1 and 50, got 0"), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockAPIFacade)
+			mockAPI.On("GetRandomQuoteWithImage", mock.Anything).
+				Return(api.PairResult{Quote: "", Image: image.NewRGBA(image.Rect(0, 0, 1, 1))}, tt.err)
+			app := &WebApp{
+				API: mockAPI,
+			}
+			req := httptest.NewRequest("GET", "/", nil)
+			recorder := httptest.NewRecorder()
+			app.HandleRandomImageQuote(recorder, req)
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+func TestHandleRandomImageQuote_JSONErrorBody(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	mockAPI.On("GetRandomQuoteWithImage", mock.Anything).
+		Return(api.PairResult{}, &facade.QuoteFetchError{Err: shared.StatusError(http.StatusServiceUnavailable)})
+	app := &WebApp{
+		API: mockAPI,
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "abc123")
+	recorder := httptest.NewRecorder()
+	WithRequestID(app.HandleRandomImageQuote)(recorder, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":{"code":"upstream_unavailable","message":"Failed to fetch data","request_id":"abc123"}}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	app.HandleRandomImageQuote(recorder, httptest.NewRequest("GET", "/?width=wide", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.JSONEq(t, `{"error":{"code":"invalid_input","message":"Invalid request parameters"}}`, recorder.Body.String())
+}
+
+func TestHandleRandomImageQuote_QuoteCard(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	mockAPI.On("GetQuoteCard", mock.Anything, mock.Anything).
+		Return(image.NewRGBA(image.Rect(0, 0, 1, 1)), nil)
+	app := &WebApp{
+		API: mockAPI,
+	}
+	req := httptest.NewRequest("GET", "/?card=true&align=right&background=shadow", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleRandomImageQuote(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code, "Expected status OK (200)")
+	mockAPI.AssertNotCalled(t, "GetRandomQuoteWithImage", mock.Anything)
+}
+
+func TestHandleRandomImageQuote_InvalidCardParameters(t *testing.T) {
+	app := &WebApp{
+		API: new(MockAPIFacade),
+	}
+	for _, query := range []string{"valign=sideways", "margin=-5", "margin=wide"} {
+		req := httptest.NewRequest("GET", "/?card=true&"+query, nil)
+		recorder := httptest.NewRecorder()
+		app.HandleRandomImageQuote(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, "Expected error 400 for %s", query)
+	}
+}
+
+func TestHandleRandomImageQuote_UnknownFilter(t *testing.T) {
+	mockAPI := new(MockAPIFacade)
+	app := &WebApp{
+		API: mockAPI,
+	}
+	req := httptest.NewRequest("GET", "/?filters=sepia", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleRandomImageQuote(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "Expected error 400")
+	mockAPI.AssertNotCalled(t, "GetRandomQuoteWithImage", mock.Anything)
+}
+
 func TestParseRequest(t *testing.T) {
 	assert := assert.New(t)
 	api := facade.NewAPIFacade()
@@ -73,3 +193,46 @@ func TestParseRequest(t *testing.T) {
 	assert.Equal(400, app.(*WebApp).AppOptions.ImageHeight, "ImageHeight should be parsed correctly")
 	assert.Equal([]string{"grayscale", "blur"}, app.(*WebApp).AppOptions.Filters, "Filters should be parsed correctly")
 }
+
+func TestParseRequest_Defaults(t *testing.T) {
+	assert := assert.New(t)
+	defaults := app.Options{QuoteCategory: 5, ImageWidth: 300, ImageHeight: 200, Filters: []string{"grayscale"}, ImageID: "7"}
+	webApp := NewWebApp(facade.NewAPIFacade(), 8080, WithDefaults(defaults)).(*WebApp)
+
+	webApp.IncomingRequest = httptest.NewRequest("GET", "/?width=100", nil)
+	err := webApp.ParseRequest()
+	assert.Nil(err, "Expected no error")
+	assert.Equal(5, webApp.AppOptions.QuoteCategory, "QuoteCategory should default")
+	assert.Equal(100, webApp.AppOptions.ImageWidth, "ImageWidth should be parsed correctly")
+	assert.Equal(200, webApp.AppOptions.ImageHeight, "ImageHeight should default")
+	assert.Equal([]string{"grayscale"}, webApp.AppOptions.Filters, "Filters should default")
+	assert.Equal("7", webApp.AppOptions.ImageID, "ImageID should default")
+
+	webApp.IncomingRequest = httptest.NewRequest("GET", "/?filters=&image_id=", nil)
+	err = webApp.ParseRequest()
+	assert.Nil(err, "Expected no error")
+	assert.Equal([]string{""}, webApp.AppOptions.Filters, "An empty filters parameter should clear the default")
+	assert.Equal("", webApp.AppOptions.ImageID, "An empty image_id parameter should clear the default")
+}
+
+func TestWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.FormatJSON, slog.LevelInfo)
+	assert.NoError(t, err)
+	mockAPI := new(MockAPIFacade)
+	mockAPI.On("GetRandomQuoteWithImage", mock.Anything).
+		Return(api.PairResult{}, errors.New("quote api down"))
+	app := NewWebApp(mockAPI, 0, WithLogger(logger)).(*WebApp)
+	handler := WithRequestID(app.HandleRandomImageQuote)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "abc123")
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+	assert.Equal(t, "abc123", recorder.Header().Get(RequestIDHeader))
+	assert.Contains(t, buf.String(), `"msg":"Failed to fetch data","error":"failed to get random quote with image: quote api down","request_id":"abc123"`)
+
+	recorder = httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest("GET", "/", nil))
+	assert.Len(t, recorder.Header().Get(RequestIDHeader), 16, "Expected a generated request ID")
+}
@@ -0,0 +1,348 @@
+// Package card renders a quote and its author over an image as a single picture.
+package card
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Alignment is the horizontal alignment of the quote text.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// VerticalAlignment is the vertical position of the quote text block.
+type VerticalAlignment int
+
+const (
+	AlignTop VerticalAlignment = iota
+	AlignMiddle
+	AlignBottom
+)
+
+// Background controls how the text is kept readable over the image.
+type Background int
+
+const (
+	BackgroundScrim Background = iota
+	BackgroundShadow
+	BackgroundNone
+)
+
+const (
+	DefaultMargin       = 32
+	DefaultMinFontSize  = 6
+	DefaultMaxFontSize  = 64
+	DefaultLineSpacing  = 1.3
+	DefaultScrimOpacity = 0.55
+	// authorScale is the author font size relative to the quote font size.
+	authorScale = 0.7
+)
+
+var (
+	regularFont = mustParseFont(goregular.TTF)
+	italicFont  = mustParseFont(goitalic.TTF)
+)
+
+// CardConfigBuilder provides methods for building a cardConfig instance.
+type CardConfigBuilder struct {
+	config cardConfig
+}
+
+// cardConfig represents configuration options for rendering a quote card.
+type cardConfig struct {
+	Margin            int
+	Alignment         Alignment
+	VerticalAlignment VerticalAlignment
+	Background        Background
+	MinFontSize       float64
+	MaxFontSize       float64
+	LineSpacing       float64
+	TextColor         color.Color
+}
+
+// NewCardConfigBuilder creates a new CardConfigBuilder instance with centred text over a scrim.
+func NewCardConfigBuilder() *CardConfigBuilder {
+	return &CardConfigBuilder{
+		config: cardConfig{
+			Margin:            DefaultMargin,
+			Alignment:         AlignCenter,
+			VerticalAlignment: AlignMiddle,
+			Background:        BackgroundScrim,
+			MinFontSize:       DefaultMinFontSize,
+			MaxFontSize:       DefaultMaxFontSize,
+			LineSpacing:       DefaultLineSpacing,
+			TextColor:         color.White,
+		},
+	}
+}
+
+// WithMargin sets the margin in pixels between the text and the image edges and returns the builder instance.
+func (ccb *CardConfigBuilder) WithMargin(margin int) *CardConfigBuilder {
+	ccb.config.Margin = max(margin, 0)
+	return ccb
+}
+
+// WithAlignment sets the horizontal text alignment and returns the builder instance.
+func (ccb *CardConfigBuilder) WithAlignment(alignment Alignment) *CardConfigBuilder {
+	ccb.config.Alignment = alignment
+	return ccb
+}
+
+// WithVerticalAlignment sets the vertical position of the text block and returns the builder instance.
+func (ccb *CardConfigBuilder) WithVerticalAlignment(alignment VerticalAlignment) *CardConfigBuilder {
+	ccb.config.VerticalAlignment = alignment
+	return ccb
+}
+
+// WithBackground sets how the text is kept readable and returns the builder instance.
+func (ccb *CardConfigBuilder) WithBackground(background Background) *CardConfigBuilder {
+	ccb.config.Background = background
+	return ccb
+}
+
+// WithFontSizeRange sets the range searched when fitting the text to the image and returns the builder instance.
+func (ccb *CardConfigBuilder) WithFontSizeRange(minSize, maxSize float64) *CardConfigBuilder {
+	if minSize > 0 && maxSize >= minSize {
+		ccb.config.MinFontSize = minSize
+		ccb.config.MaxFontSize = maxSize
+	}
+	return ccb
+}
+
+// WithTextColor sets the text color and returns the builder instance.
+func (ccb *CardConfigBuilder) WithTextColor(c color.Color) *CardConfigBuilder {
+	ccb.config.TextColor = c
+	return ccb
+}
+
+// Build constructs and returns a cardConfig instance.
+func (ccb *CardConfigBuilder) Build() cardConfig {
+	return ccb.config
+}
+
+// layout holds the result of fitting the text into the available area.
+type layout struct {
+	face         font.Face
+	authorFace   font.Face
+	lines        []string
+	authorLine   string
+	authorOffset float64
+	lineHeight   float64
+	width        float64
+	height       float64
+	fontSize     float64
+}
+
+// Render draws the quote and, if not empty, its author over a copy of img.
+// The largest font size in the configured range whose word-wrapped text fits
+// inside the margins is used; if none fits, the minimum size is used.
+func Render(img image.Image, quote, author string, cardCnfgBldr *CardConfigBuilder) (image.Image, error) {
+	cfg := cardCnfgBldr.Build()
+	if img == nil || img.Bounds().Empty() {
+		return nil, fmt.Errorf("cannot render quote card on an empty image")
+	}
+	quote = strings.TrimSpace(quote)
+	if quote == "" {
+		return nil, fmt.Errorf("cannot render quote card without a quote")
+	}
+
+	dc := gg.NewContextForImage(img)
+	width, height := float64(dc.Width()), float64(dc.Height())
+	margin := min(float64(cfg.Margin), min(width, height)/8)
+
+	l := fitText(dc, quote, strings.TrimSpace(author), width-2*margin, height-2*margin, cfg)
+
+	top := margin
+	switch cfg.VerticalAlignment {
+	case AlignMiddle:
+		top = (height - l.height) / 2
+	case AlignBottom:
+		top = height - margin - l.height
+	}
+
+	anchorX, ax := margin, 0.0
+	blockX := margin
+	switch cfg.Alignment {
+	case AlignCenter:
+		anchorX, ax = width/2, 0.5
+		blockX = (width - l.width) / 2
+	case AlignRight:
+		anchorX, ax = width-margin, 1
+		blockX = width - margin - l.width
+	}
+
+	switch cfg.Background {
+	case BackgroundScrim:
+		padding := min(margin/2, l.fontSize/2)
+		dc.SetRGBA(0, 0, 0, DefaultScrimOpacity)
+		dc.DrawRoundedRectangle(blockX-padding, top-padding, l.width+2*padding, l.height+2*padding, padding)
+		dc.Fill()
+	case BackgroundShadow:
+		offset := max(l.fontSize/16, 1)
+		dc.SetRGBA(0, 0, 0, 0.8)
+		drawText(dc, l, anchorX+offset, top+offset, ax)
+	}
+
+	dc.SetColor(cfg.TextColor)
+	drawText(dc, l, anchorX, top, ax)
+	return dc.Image(), nil
+}
+
+// drawText draws the laid out quote lines and author line starting at top.
+func drawText(dc *gg.Context, l layout, anchorX, top, ax float64) {
+	dc.SetFontFace(l.face)
+	y := top
+	for _, line := range l.lines {
+		dc.DrawStringAnchored(line, anchorX, y, ax, 1)
+		y += l.lineHeight
+	}
+	if l.authorLine != "" {
+		dc.SetFontFace(l.authorFace)
+		dc.DrawStringAnchored(l.authorLine, anchorX, top+l.authorOffset, ax, 1)
+	}
+}
+
+// fitText finds the largest font size whose wrapped text fits within maxWidth x maxHeight.
+func fitText(dc *gg.Context, quote, author string, maxWidth, maxHeight float64, cfg cardConfig) layout {
+	var l layout
+	for size := cfg.MaxFontSize; size >= cfg.MinFontSize; size-- {
+		l = measure(dc, quote, author, maxWidth, size, cfg.LineSpacing)
+		if l.width <= maxWidth && l.height <= maxHeight {
+			return l
+		}
+	}
+	return measure(dc, quote, author, maxWidth, cfg.MinFontSize, cfg.LineSpacing)
+}
+
+// measure wraps the text at the given font size and returns its layout.
+func measure(dc *gg.Context, quote, author string, maxWidth, size, lineSpacing float64) layout {
+	l := layout{
+		face:     truetype.NewFace(regularFont, &truetype.Options{Size: size}),
+		fontSize: size,
+	}
+	dc.SetFontFace(l.face)
+	l.lines = dc.WordWrap(quote, maxWidth)
+	l.lineHeight = dc.FontHeight() * lineSpacing
+	for _, line := range l.lines {
+		w, _ := dc.MeasureString(line)
+		l.width = max(l.width, w)
+	}
+	l.height = float64(len(l.lines))*l.lineHeight - (lineSpacing-1)*dc.FontHeight()
+
+	if author != "" {
+		l.authorFace = truetype.NewFace(italicFont, &truetype.Options{Size: size * authorScale})
+		dc.SetFontFace(l.authorFace)
+		l.authorLine = "— " + author
+		l.authorOffset = l.height + l.lineHeight - dc.FontHeight()
+		authorWidth, _ := dc.MeasureString(l.authorLine)
+		l.width = max(l.width, authorWidth)
+		l.height = l.authorOffset + dc.FontHeight()
+	}
+	return l
+}
+
+// mustParseFont parses an embedded TrueType font, panicking if it is invalid.
+func mustParseFont(ttf []byte) *truetype.Font {
+	f, err := truetype.Parse(ttf)
+	if err != nil {
+		panic(fmt.Sprintf("card: invalid embedded font: %v", err))
+	}
+	return f
+}
+
+// ParseAlignment parses a horizontal alignment name: left, center or right.
+func ParseAlignment(name string) (Alignment, error) {
+	switch strings.ToLower(name) {
+	case "left":
+		return AlignLeft, nil
+	case "center", "centre":
+		return AlignCenter, nil
+	case "right":
+		return AlignRight, nil
+	}
+	return AlignCenter, fmt.Errorf("invalid alignment %q", name)
+}
+
+// ParseVerticalAlignment parses a vertical alignment name: top, middle or bottom.
+func ParseVerticalAlignment(name string) (VerticalAlignment, error) {
+	switch strings.ToLower(name) {
+	case "top":
+		return AlignTop, nil
+	case "middle":
+		return AlignMiddle, nil
+	case "bottom":
+		return AlignBottom, nil
+	}
+	return AlignMiddle, fmt.Errorf("invalid vertical alignment %q", name)
+}
+
+// ParseBackground parses a background name: scrim, shadow or none.
+func ParseBackground(name string) (Background, error) {
+	switch strings.ToLower(name) {
+	case "scrim":
+		return BackgroundScrim, nil
+	case "shadow":
+		return BackgroundShadow, nil
+	case "none":
+		return BackgroundNone, nil
+	}
+	return BackgroundScrim, fmt.Errorf("invalid background %q", name)
+}
+
+// ParseMargin parses a margin in pixels, which must be a non-negative integer.
+func ParseMargin(value string) (int, error) {
+	margin, err := strconv.Atoi(value)
+	if err != nil || margin < 0 {
+		return DefaultMargin, fmt.Errorf("invalid margin %q", value)
+	}
+	return margin, nil
+}
+
+// ParseCardConfig creates a CardConfigBuilder from alignment, vertical alignment and
+// background names and a margin in pixels, keeping the defaults for empty values.
+func ParseCardConfig(alignment, verticalAlignment, background, margin string) (*CardConfigBuilder, error) {
+	builder := NewCardConfigBuilder()
+	if alignment != "" {
+		a, err := ParseAlignment(alignment)
+		if err != nil {
+			return nil, err
+		}
+		builder.WithAlignment(a)
+	}
+	if verticalAlignment != "" {
+		v, err := ParseVerticalAlignment(verticalAlignment)
+		if err != nil {
+			return nil, err
+		}
+		builder.WithVerticalAlignment(v)
+	}
+	if background != "" {
+		b, err := ParseBackground(background)
+		if err != nil {
+			return nil, err
+		}
+		builder.WithBackground(b)
+	}
+	if margin != "" {
+		m, err := ParseMargin(margin)
+		if err != nil {
+			return nil, err
+		}
+		builder.WithMargin(m)
+	}
+	return builder, nil
+}
@@ -0,0 +1,95 @@
+package card
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/fogleman/gg"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGrayImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{128, 128, 128, 255}}, image.Point{}, draw.Src)
+	return img
+}
+
+func TestRender_DrawsOverCopy(t *testing.T) {
+	original := newGrayImage(400, 300)
+	result, err := Render(original, "The only way to do great work is to love what you do.", "Steve Jobs", NewCardConfigBuilder())
+	assert.NoError(t, err)
+	assert.Equal(t, original.Bounds(), result.Bounds(), "Card should keep the image size")
+	assert.Equal(t, color.RGBA{128, 128, 128, 255}, original.At(200, 150), "Original image should not be modified")
+
+	changed := false
+	for y := 0; y < 300 && !changed; y++ {
+		for x := 0; x < 400; x++ {
+			if result.At(x, y) != original.At(x, y) {
+				changed = true
+				break
+			}
+		}
+	}
+	assert.True(t, changed, "Expected the quote to be drawn onto the card")
+}
+
+func TestRender_Errors(t *testing.T) {
+	_, err := Render(image.NewRGBA(image.Rect(0, 0, 0, 0)), "quote", "", NewCardConfigBuilder())
+	assert.Error(t, err, "Expected error for an empty image")
+
+	_, err = Render(newGrayImage(10, 10), "  ", "", NewCardConfigBuilder())
+	assert.Error(t, err, "Expected error for an empty quote")
+}
+
+func TestFitText_ShrinksLongQuotes(t *testing.T) {
+	dc := gg.NewContext(400, 300)
+	cfg := NewCardConfigBuilder().Build()
+
+	short := fitText(dc, "Short quote.", "", 336, 236, cfg)
+	long := fitText(dc, "A much longer quote that needs to wrap across several lines before it fits inside the card margins, and so it must be rendered with a smaller font.", "Someone", 336, 236, cfg)
+
+	assert.Less(t, long.fontSize, short.fontSize, "Longer quotes should use a smaller font")
+	assert.LessOrEqual(t, long.width, 336.0)
+	assert.LessOrEqual(t, long.height, 236.0)
+	assert.Greater(t, len(long.lines), 1, "Long quotes should be word wrapped")
+	assert.Equal(t, "— Someone", long.authorLine)
+}
+
+func TestCardConfigBuilder(t *testing.T) {
+	expected := cardConfig{
+		Margin:            10,
+		Alignment:         AlignRight,
+		VerticalAlignment: AlignBottom,
+		Background:        BackgroundShadow,
+		MinFontSize:       8,
+		MaxFontSize:       20,
+		LineSpacing:       DefaultLineSpacing,
+		TextColor:         color.Black,
+	}
+	result := NewCardConfigBuilder().WithMargin(10).WithAlignment(AlignRight).WithVerticalAlignment(AlignBottom).
+		WithBackground(BackgroundShadow).WithFontSizeRange(8, 20).WithTextColor(color.Black).Build()
+	assert.Equal(t, expected, result, "Config Builder does not create the expected instance")
+}
+
+func TestParseCardConfig(t *testing.T) {
+	builder, err := ParseCardConfig("left", "", "none", "12")
+	assert.NoError(t, err)
+	assert.Equal(t, AlignLeft, builder.Build().Alignment)
+	assert.Equal(t, AlignMiddle, builder.Build().VerticalAlignment)
+	assert.Equal(t, BackgroundNone, builder.Build().Background)
+	assert.Equal(t, 12, builder.Build().Margin)
+
+	builder, err = ParseCardConfig("", "", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultMargin, builder.Build().Margin)
+
+	_, err = ParseCardConfig("", "sideways", "", "")
+	assert.Error(t, err, "Expected error for an invalid vertical alignment")
+
+	for _, margin := range []string{"-1", "wide", "1.5"} {
+		_, err = ParseCardConfig("", "", "", margin)
+		assert.ErrorContains(t, err, "invalid margin", margin)
+	}
+}
@@ -0,0 +1,261 @@
+// Package config loads the application configuration by layering defaults, a named profile,
+// a YAML, JSON or TOML config file, TUCOWS_* environment variables and command-line flags.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ramyad/tucows/internal/api/breaker"
+	"github.com/ramyad/tucows/internal/api/facade"
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/api/registry"
+	"github.com/ramyad/tucows/internal/card"
+	"github.com/ramyad/tucows/internal/logging"
+)
+
+const (
+	DefaultPort = 8080
+	// MoodFilters and MoodPalette are the supported values of Pairing.Mood.
+	MoodFilters = "filters"
+	MoodPalette = "palette"
+	// ColorAuto detects the color support of the terminal; the other Color values force a mode.
+	ColorAuto      = "auto"
+	ColorTrueColor = "truecolor"
+	Color256       = "256"
+	Color16        = "16"
+	ColorNone      = "none"
+	// RenderAuto picks the characters or the graphics protocol the image is drawn with from
+	// the terminal capabilities; the other Render values force them.
+	RenderAuto     = "auto"
+	RenderBlocks   = "blocks"
+	RenderHalf     = "half"
+	RenderQuadrant = "quadrant"
+	RenderSextant  = "sextant"
+	RenderSixel    = "sixel"
+	RenderKitty    = "kitty"
+	RenderITerm2   = "iterm2"
+	RenderASCII    = "ascii"
+	RenderBraille  = "braille"
+	// DefaultRamp is the character ramp of ASCII art, from dark to bright.
+	DefaultRamp = " .:-=+*#%@"
+	// DitherNone, DitherFloydSteinberg, DitherAtkinson and DitherBayer are the supported values of Terminal.Dither.
+	DitherNone           = "none"
+	DitherFloydSteinberg = "floyd-steinberg"
+	DitherAtkinson       = "atkinson"
+	DitherBayer          = "bayer"
+)
+
+// Config is the validated configuration shared by the terminal and web applications.
+type Config struct {
+	// Profile is the name of the profile applied on top of the defaults, if any.
+	Profile string
+	// File is the path of the config file that was loaded, if any.
+	File       string
+	Log        LogConfig
+	Quote      QuoteConfig
+	Image      ImageConfig
+	Card       CardConfig
+	Web        WebConfig
+	Terminal   TerminalConfig
+	Resilience ResilienceConfig
+	Pairing    PairingConfig
+}
+
+// LogConfig configures the application logger.
+type LogConfig struct {
+	Level  string
+	Format string
+	// File is the log destination; standard error when empty.
+	File string
+}
+
+// QuoteConfig configures the quote provider and the default quote category.
+type QuoteConfig struct {
+	Provider      string
+	Category      int
+	RetryAttempts int
+}
+
+// ImageConfig configures the image provider and the default image request.
+type ImageConfig struct {
+	Provider         string
+	Width            int
+	Height           int
+	Filters          []string
+	ID               string
+	RetryAttempts    int
+	MaxResponseBytes int64
+	MaxPixels        int
+}
+
+// CardConfig configures quote card rendering.
+type CardConfig struct {
+	Enabled    bool
+	Align      string
+	VAlign     string
+	Background string
+	// Margin is the distance in pixels between the text and the image edges.
+	Margin int
+}
+
+// WebConfig configures the web application.
+type WebConfig struct {
+	Port int
+}
+
+// TerminalConfig configures the terminal application.
+type TerminalConfig struct {
+	// Interval, when positive, shows a new pair every interval as a slideshow.
+	Interval time.Duration
+	// Color is the color mode of the rendered image, one of the Color values.
+	Color string
+	// Render selects the characters or the graphics protocol the image is drawn with, one of the Render values.
+	Render string
+	// Ramp and Edges configure ASCII art: the characters from dark to bright, and whether
+	// edges are drawn with line characters.
+	Ramp  string
+	Edges bool
+	// Dither is how colors are reduced to a limited palette, one of the Dither values.
+	Dither string
+}
+
+// ResilienceConfig configures how provider failures and slow providers are handled.
+type ResilienceConfig struct {
+	Degraded         bool
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	QuoteHedgeDelay  time.Duration
+	ImageHedgeDelay  time.Duration
+}
+
+// PairingConfig configures prefetching and how images are matched to quotes.
+type PairingConfig struct {
+	Prefetch       int
+	Mood           string
+	MoodCandidates int
+	Keywords       int
+}
+
+// Default returns the built-in defaults.
+func Default() Config {
+	return Config{
+		Log: LogConfig{
+			Level:  "info",
+			Format: logging.FormatText,
+		},
+		Quote: QuoteConfig{
+			Provider:      registry.DefaultQuoteProvider,
+			RetryAttempts: quoteapi.RetryAttempts,
+		},
+		Image: ImageConfig{
+			Provider:         registry.DefaultImageProvider,
+			Width:            imageapi.DefaultImageWidth,
+			Height:           imageapi.DefaultImageHeight,
+			RetryAttempts:    imageapi.RetryAttempts,
+			MaxResponseBytes: imageapi.DefaultMaxResponseBytes,
+			MaxPixels:        imageapi.DefaultMaxImagePixels,
+		},
+		Card: CardConfig{
+			Margin: card.DefaultMargin,
+		},
+		Web: WebConfig{
+			Port: DefaultPort,
+		},
+		Terminal: TerminalConfig{
+			Color:  ColorAuto,
+			Render: RenderAuto,
+			Ramp:   DefaultRamp,
+			Dither: DitherFloydSteinberg,
+		},
+		Resilience: ResilienceConfig{
+			BreakerThreshold: breaker.DefaultFailureThreshold,
+			BreakerCooldown:  breaker.DefaultCooldown,
+		},
+		Pairing: PairingConfig{
+			MoodCandidates: facade.DefaultMoodCandidates,
+		},
+	}
+}
+
+// Validate reports every invalid value of the configuration.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	check(c.Log.Format == logging.FormatText || c.Log.Format == logging.FormatJSON, "log.format must be %s or %s, got %q", logging.FormatText, logging.FormatJSON, c.Log.Format)
+
+	check(c.Quote.Provider != "", "quote.provider must not be empty")
+	check(c.Quote.Category >= 0 && c.Quote.Category <= quoteapi.MaxKeyValue, "quote.category must be between 0 and %d, got %d", quoteapi.MaxKeyValue, c.Quote.Category)
+	check(c.Quote.RetryAttempts >= 1, "quote.retry_attempts must be at least 1, got %d", c.Quote.RetryAttempts)
+
+	check(c.Image.Provider != "", "image.provider must not be empty")
+	check(c.Image.Width >= 1 && c.Image.Width <= imageapi.MaxImageWidth, "image.width must be between 1 and %d, got %d", imageapi.MaxImageWidth, c.Image.Width)
+	check(c.Image.Height >= 1 && c.Image.Height <= imageapi.MaxImageHeight, "image.height must be between 1 and %d, got %d", imageapi.MaxImageHeight, c.Image.Height)
+	for _, filter := range c.Image.Filters {
+		check(filter == imageapi.ImageFilterGrayscale || filter == imageapi.ImageFilterBlur, "image.filters: unknown image filter %q", filter)
+	}
+	check(c.Image.RetryAttempts >= 1, "image.retry_attempts must be at least 1, got %d", c.Image.RetryAttempts)
+	check(c.Image.MaxResponseBytes >= 1, "image.max_response_bytes must be positive, got %d", c.Image.MaxResponseBytes)
+	check(c.Image.MaxPixels >= 1, "image.max_pixels must be positive, got %d", c.Image.MaxPixels)
+
+	if _, err := c.Card.Builder(); err != nil {
+		errs = append(errs, fmt.Errorf("card: %w", err))
+	}
+
+	check(c.Web.Port >= 1 && c.Web.Port <= 65535, "web.port must be between 1 and 65535, got %d", c.Web.Port)
+	check(c.Terminal.Interval >= 0, "terminal.interval must not be negative, got %s", c.Terminal.Interval)
+	check(slices.Contains([]string{ColorAuto, ColorTrueColor, Color256, Color16, ColorNone}, c.Terminal.Color), "terminal.color must be %s, %s, %s, %s or %s, got %q", ColorAuto, ColorTrueColor, Color256, Color16, ColorNone, c.Terminal.Color)
+	renders := []string{RenderAuto, RenderBlocks, RenderHalf, RenderQuadrant, RenderSextant, RenderSixel, RenderKitty, RenderITerm2, RenderASCII, RenderBraille}
+	check(slices.Contains(renders, c.Terminal.Render), "terminal.render must be one of %s, got %q", strings.Join(renders, ", "), c.Terminal.Render)
+	check(utf8.RuneCountInString(c.Terminal.Ramp) >= 2, "terminal.ramp must have at least 2 characters, got %q", c.Terminal.Ramp)
+	check(slices.Contains([]string{DitherNone, DitherFloydSteinberg, DitherAtkinson, DitherBayer}, c.Terminal.Dither), "terminal.dither must be %s, %s, %s or %s, got %q", DitherNone, DitherFloydSteinberg, DitherAtkinson, DitherBayer, c.Terminal.Dither)
+
+	check(c.Resilience.BreakerThreshold >= 0, "resilience.breaker_threshold must not be negative, got %d", c.Resilience.BreakerThreshold)
+	check(c.Resilience.BreakerCooldown >= 0, "resilience.breaker_cooldown must not be negative, got %s", c.Resilience.BreakerCooldown)
+	check(c.Resilience.QuoteHedgeDelay >= 0, "resilience.quote_hedge_delay must not be negative, got %s", c.Resilience.QuoteHedgeDelay)
+	check(c.Resilience.ImageHedgeDelay >= 0, "resilience.image_hedge_delay must not be negative, got %s", c.Resilience.ImageHedgeDelay)
+
+	check(c.Pairing.Prefetch >= 0, "pairing.prefetch must not be negative, got %d", c.Pairing.Prefetch)
+	check(slices.Contains([]string{"", MoodFilters, MoodPalette}, c.Pairing.Mood), "pairing.mood must be %s or %s, got %q", MoodFilters, MoodPalette, c.Pairing.Mood)
+	check(c.Pairing.MoodCandidates >= 1, "pairing.mood_candidates must be at least 1, got %d", c.Pairing.MoodCandidates)
+	check(c.Pairing.Keywords >= 0, "pairing.keywords must not be negative, got %d", c.Pairing.Keywords)
+
+	return errors.Join(errs...)
+}
+
+// CardConfigBuilder returns the quote card layout, or nil when cards are disabled.
+func (c Config) CardConfigBuilder() (*card.CardConfigBuilder, error) {
+	if !c.Card.Enabled {
+		return nil, nil
+	}
+	return c.Card.Builder()
+}
+
+// Builder returns the quote card layout, whether or not cards are enabled.
+func (c CardConfig) Builder() (*card.CardConfigBuilder, error) {
+	return card.ParseCardConfig(c.Align, c.VAlign, c.Background, strconv.Itoa(c.Margin))
+}
+
+// RegistrySettings returns the settings of the built-in network providers.
+func (c Config) RegistrySettings() registry.Settings {
+	return registry.Settings{
+		QuoteRetryAttempts:    c.Quote.RetryAttempts,
+		ImageRetryAttempts:    c.Image.RetryAttempts,
+		MaxImageResponseBytes: c.Image.MaxResponseBytes,
+		MaxImagePixels:        c.Image.MaxPixels,
+	}
+}
@@ -0,0 +1,301 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envPrefix = "TUCOWS_"
+	// ConfigFlag and ProfileFlag select the config file and the profile on the command line.
+	ConfigFlag  = "config"
+	ProfileFlag = "profile"
+	// profileKey and profilesKey are the config file keys selecting and defining profiles.
+	profileKey  = "profile"
+	profilesKey = "profiles"
+)
+
+// loader holds the options of a single Load call.
+type loader struct {
+	defaults  Config
+	flags     *flag.FlagSet
+	flagKeys  []string
+	lookupEnv func(string) (string, bool)
+}
+
+// Option configures how Load builds the configuration.
+type Option func(*loader)
+
+// WithDefaults replaces the built-in defaults, e.g. with application-specific image sizes.
+func WithDefaults(defaults Config) Option {
+	return func(l *loader) {
+		l.defaults = defaults
+	}
+}
+
+// WithFlagSet parses the arguments with flags, so that applications can add flags of their own.
+func WithFlagSet(flags *flag.FlagSet) Option {
+	return func(l *loader) {
+		l.flags = flags
+	}
+}
+
+// WithFlags registers command-line flags for the settings with the given keys.
+// Settings without a flag can still be set in the config file or the environment.
+func WithFlags(keys ...string) Option {
+	return func(l *loader) {
+		l.flagKeys = append(l.flagKeys, keys...)
+	}
+}
+
+// WithLookupEnv replaces os.LookupEnv for reading TUCOWS_* environment variables.
+func WithLookupEnv(lookupEnv func(string) (string, bool)) Option {
+	return func(l *loader) {
+		l.lookupEnv = lookupEnv
+	}
+}
+
+// Load parses args and builds the configuration from these layers, each overriding the previous:
+//
+//  1. the defaults
+//  2. the profile selected by -profile, TUCOWS_PROFILE or the config file
+//  3. the config file selected by -config or TUCOWS_CONFIG, in YAML, JSON or TOML
+//  4. TUCOWS_* environment variables, e.g. TUCOWS_IMAGE_WIDTH for image.width
+//  5. command-line flags that were given explicitly
+//
+// The result is validated before it is returned.
+func Load(args []string, opts ...Option) (*Config, error) {
+	l := &loader{
+		defaults:  Default(),
+		flags:     flag.NewFlagSet(os.Args[0], flag.ContinueOnError),
+		lookupEnv: os.LookupEnv,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	configPath := l.flags.String(ConfigFlag, "", "Load settings from a YAML, JSON or TOML config file")
+	profileName := l.flags.String(ProfileFlag, "", "Apply a named settings profile, e.g. kiosk or dev")
+	flagValues := map[string]*flagValue{}
+	for _, key := range l.flagKeys {
+		s, ok := lookupSetting(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown setting %q", key)
+		}
+		value := &flagValue{setting: s, value: s.get(&l.defaults)}
+		l.flags.Var(value, s.flag, s.usage)
+		flagValues[s.flag] = value
+	}
+	if err := l.flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	flagLayer := map[string]string{}
+	l.flags.Visit(func(f *flag.Flag) {
+		if value, ok := flagValues[f.Name]; ok {
+			flagLayer[value.setting.key] = value.value
+		}
+	})
+
+	envLayer := map[string]string{}
+	for _, s := range settings {
+		if value, ok := l.lookupEnv(EnvName(s.key)); ok {
+			envLayer[s.key] = value
+		}
+	}
+
+	if *configPath == "" {
+		*configPath, _ = l.lookupEnv(EnvName(ConfigFlag))
+	}
+	fileLayer, fileProfiles := map[string]string{}, map[string]map[string]string{}
+	if *configPath != "" {
+		var err error
+		fileLayer, fileProfiles, err = readFile(*configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if *profileName == "" {
+		*profileName, _ = l.lookupEnv(EnvName(ProfileFlag))
+	}
+	if *profileName == "" {
+		*profileName = fileLayer[profileKey]
+	}
+	delete(fileLayer, profileKey)
+
+	config := l.defaults
+	config.File = *configPath
+	config.Image.Filters = slices.Clone(config.Image.Filters)
+	if *profileName != "" {
+		profile, ok := fileProfiles[*profileName]
+		if !ok {
+			profile, ok = profiles[*profileName]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q, expected one of: %s", *profileName, strings.Join(ProfileNames(fileProfiles), ", "))
+		}
+		if err := apply(&config, "profile "+*profileName, profile); err != nil {
+			return nil, err
+		}
+		config.Profile = *profileName
+	}
+	for _, layer := range []struct {
+		source string
+		values map[string]string
+	}{
+		{*configPath, fileLayer},
+		{"environment", envLayer},
+		{"flags", flagLayer},
+	} {
+		if err := apply(&config, layer.source, layer.values); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return &config, nil
+}
+
+// ProfileNames returns the names of the built-in profiles and of the given extra profiles, sorted.
+func ProfileNames(extra map[string]map[string]string) []string {
+	names := make([]string, 0, len(profiles)+len(extra))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	for name := range extra {
+		if _, ok := profiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// apply sets the given settings on config in key order, so that errors are reported deterministically.
+func apply(config *Config, source string, values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s, ok := lookupSetting(key)
+		if !ok {
+			return fmt.Errorf("%s: unknown setting %q", source, key)
+		}
+		if err := s.set(config, values[key]); err != nil {
+			return fmt.Errorf("%s: %s: %w", source, key, err)
+		}
+	}
+	return nil
+}
+
+// readFile reads a config file and returns its settings and the profiles it defines,
+// all flattened to dotted keys. The format is chosen by the file extension.
+func readFile(path string) (map[string]string, map[string]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	tree := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".json":
+		decoder := json.NewDecoder(strings.NewReader(string(data)))
+		decoder.UseNumber()
+		err = decoder.Decode(&tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, nil, fmt.Errorf("unsupported config file format %q, expected .yaml, .yml, .json or .toml", ext)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	flatten("", tree, values)
+
+	fileProfiles := map[string]map[string]string{}
+	for key, value := range values {
+		rest, ok := strings.CutPrefix(key, profilesKey+".")
+		if !ok {
+			continue
+		}
+		delete(values, key)
+		name, settingKey, ok := strings.Cut(rest, ".")
+		if !ok {
+			return nil, nil, fmt.Errorf("config file %s: profile %q must contain settings", path, name)
+		}
+		if fileProfiles[name] == nil {
+			fileProfiles[name] = map[string]string{}
+		}
+		fileProfiles[name][settingKey] = value
+	}
+	return values, fileProfiles, nil
+}
+
+// flatten stores the scalar values of tree in values under their dotted keys.
+// Lists become comma-separated values.
+func flatten(prefix string, tree map[string]any, values map[string]string) {
+	for name, node := range tree {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		switch node := node.(type) {
+		case map[string]any:
+			flatten(key, node, values)
+		case []any:
+			items := make([]string, 0, len(node))
+			for _, item := range node {
+				items = append(items, fmt.Sprint(item))
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(node)
+		}
+	}
+}
+
+// flagValue is a flag.Value that records the raw value of a setting, so that only flags
+// given explicitly override the other layers.
+type flagValue struct {
+	setting setting
+	value   string
+}
+
+func (v *flagValue) String() string {
+	return v.value
+}
+
+// Set checks that value parses for the setting and records it.
+func (v *flagValue) Set(value string) error {
+	if err := v.setting.set(&Config{}, value); err != nil {
+		return err
+	}
+	v.value = value
+	return nil
+}
+
+// IsBoolFlag lets boolean settings be given as -flag without a value.
+func (v *flagValue) IsBoolFlag() bool {
+	return v.setting.isBool
+}
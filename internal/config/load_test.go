@@ -0,0 +1,184 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// env returns a lookup function reading from the given variables only.
+func env(vars map[string]string) Option {
+	return WithLookupEnv(func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	})
+}
+
+// writeFile writes a config file into a temporary directory and returns its path.
+func writeFile(t *testing.T, name, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	return path
+}
+
+func TestLoad_defaults(t *testing.T) {
+
+	config, err := Load(nil, env(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, Default(), *config)
+}
+
+func TestLoad_precedence(t *testing.T) {
+
+	path := writeFile(t, "config.yaml", `
+image:
+  width: 100
+  height: 100
+  filters: [grayscale]
+web:
+  port: 9000
+`)
+	vars := map[string]string{
+		"TUCOWS_CONFIG":       path,
+		"TUCOWS_IMAGE_HEIGHT": "200",
+		"TUCOWS_WEB_PORT":     "9100",
+	}
+
+	config, err := Load([]string{"-port", "9200"}, env(vars), WithFlags("web.port", "image.width"))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, config.Image.Width, "File overrides defaults")
+	assert.Equal(t, 200, config.Image.Height, "Environment overrides the file")
+	assert.Equal(t, 9200, config.Web.Port, "Flags override the environment")
+	assert.Equal(t, []string{"grayscale"}, config.Image.Filters)
+}
+
+func TestLoad_flagDefaultsDoNotOverride(t *testing.T) {
+
+	config, err := Load(nil, env(map[string]string{"TUCOWS_WEB_PORT": "9100"}), WithFlags("web.port"))
+	assert.NoError(t, err)
+	assert.Equal(t, 9100, config.Web.Port, "A flag that was not given must not override the environment")
+}
+
+func TestLoad_profiles(t *testing.T) {
+
+	config, err := Load([]string{"-profile", "dev"}, env(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", config.Profile)
+	assert.Equal(t, "builtin", config.Quote.Provider)
+	assert.Equal(t, "generated", config.Image.Provider)
+	assert.Equal(t, 0, config.Resilience.BreakerThreshold)
+
+	path := writeFile(t, "config.toml", `
+profile = "wall"
+
+[image]
+provider = "generated"
+
+[profiles.wall]
+"terminal.interval" = "2m"
+image.provider = "dir:/srv/images"
+`)
+	config, err = Load(nil, env(map[string]string{"TUCOWS_CONFIG": path}))
+	assert.NoError(t, err)
+	assert.Equal(t, "wall", config.Profile)
+	assert.Equal(t, 2*time.Minute, config.Terminal.Interval)
+	assert.Equal(t, "generated", config.Image.Provider, "The file overrides its profile")
+
+	_, err = Load([]string{"-profile", "unknown"}, env(nil))
+	assert.ErrorContains(t, err, `unknown profile "unknown"`)
+}
+
+func TestLoad_jsonFile(t *testing.T) {
+
+	path := writeFile(t, "config.json", `{"image": {"max_response_bytes": 1048576}, "resilience": {"degraded": true}}`)
+
+	config, err := Load([]string{"-config", path}, env(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1<<20), config.Image.MaxResponseBytes)
+	assert.True(t, config.Resilience.Degraded)
+}
+
+func TestLoad_tomlFile(t *testing.T) {
+
+	path := writeFile(t, "config.toml", `
+# comment
+image = { width = 1_024, max_response_bytes = 1048576 }
+"image.filters" = [
+  'grayscale', # literal string
+  "blur",
+]
+
+[resilience]
+degraded = true # trailing comment
+breaker_cooldown = "1m30s"
+`)
+	config, err := Load([]string{"-config", path}, env(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 1024, config.Image.Width)
+	assert.Equal(t, int64(1<<20), config.Image.MaxResponseBytes)
+	assert.Equal(t, []string{"grayscale", "blur"}, config.Image.Filters)
+	assert.True(t, config.Resilience.Degraded)
+	assert.Equal(t, 90*time.Second, config.Resilience.BreakerCooldown)
+
+	path = writeFile(t, "config.toml", "[image]\nwidth = 1\n[image]\nwidth = 2\n")
+	_, err = Load([]string{"-config", path}, env(nil))
+	assert.ErrorContains(t, err, "failed to parse config file")
+}
+
+func TestLoad_errors(t *testing.T) {
+
+	_, err := Load(nil, env(map[string]string{"TUCOWS_IMAGE_WIDTH": "wide"}))
+	assert.ErrorContains(t, err, "image.width")
+
+	path := writeFile(t, "config.yaml", "image:\n  colour: red\n")
+	_, err = Load([]string{"-config", path}, env(nil))
+	assert.ErrorContains(t, err, `unknown setting "image.colour"`)
+
+	_, err = Load([]string{"-config", writeFile(t, "config.ini", "")}, env(nil))
+	assert.ErrorContains(t, err, "unsupported config file format")
+
+	_, err = Load([]string{"-filters", "sepia", "-port", "0"}, env(nil), WithFlags("image.filters", "web.port"))
+	assert.ErrorContains(t, err, `unknown image filter "sepia"`)
+	assert.ErrorContains(t, err, "web.port")
+
+	_, err = Load([]string{"-width", "wide"}, env(nil), WithFlags("image.width"))
+	assert.Error(t, err)
+
+	_, err = Load(nil, env(map[string]string{"TUCOWS_CARD_MARGIN": "-1"}))
+	assert.ErrorContains(t, err, "invalid margin")
+
+	_, err = Load(nil, env(map[string]string{"TUCOWS_LOG_LEVEL": "loud", "TUCOWS_LOG_FORMAT": "xml"}))
+	assert.ErrorContains(t, err, "log.level")
+	assert.ErrorContains(t, err, "log.format")
+
+	_, err = Load(nil, env(map[string]string{"TUCOWS_TERMINAL_COLOR": "rainbow", "TUCOWS_TERMINAL_RENDER": "pixels", "TUCOWS_TERMINAL_RAMP": "#", "TUCOWS_TERMINAL_DITHER": "random"}))
+	assert.ErrorContains(t, err, "terminal.color")
+	assert.ErrorContains(t, err, "terminal.render")
+	assert.ErrorContains(t, err, "terminal.ramp")
+	assert.ErrorContains(t, err, "terminal.dither")
+}
+
+func TestLoad_withFlagSet(t *testing.T) {
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	list := flags.Bool("list", false, "")
+	defaults := Default()
+	defaults.Image.Width = 40
+
+	config, err := Load([]string{"-list", "-card", "-margin", "12"}, env(nil), WithFlagSet(flags), WithDefaults(defaults), WithFlags("card.enabled", "card.margin", "image.width"))
+	assert.NoError(t, err)
+	assert.True(t, *list)
+	assert.True(t, config.Card.Enabled)
+	assert.Equal(t, 12, config.Card.Margin)
+	assert.Equal(t, 40, config.Image.Width)
+	assert.Equal(t, "40", flags.Lookup("width").DefValue, "Flag defaults reflect the given defaults")
+}
+
+func TestEnvName(t *testing.T) {
+
+	assert.Equal(t, "TUCOWS_RESILIENCE_BREAKER_COOLDOWN", EnvName("resilience.breaker_cooldown"))
+}
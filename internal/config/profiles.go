@@ -0,0 +1,22 @@
+package config
+
+// profiles are the built-in named profiles. Each maps setting keys to values applied on
+// top of the defaults; config files may define more under "profiles".
+var profiles = map[string]map[string]string{
+	// kiosk suits an unattended display: it keeps serving during outages and shows cards.
+	"kiosk": {
+		"card.enabled":        "true",
+		"terminal.interval":   "1m",
+		"resilience.degraded": "true",
+		"pairing.prefetch":    "2",
+		"pairing.mood":        MoodFilters,
+	},
+	// dev works offline with fast failures.
+	"dev": {
+		"quote.provider":               "builtin",
+		"quote.retry_attempts":         "1",
+		"image.provider":               "generated",
+		"image.retry_attempts":         "1",
+		"resilience.breaker_threshold": "0",
+	},
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting is a single configuration value addressed by its key in config files, by its
+// TUCOWS_* environment variable and, when an application registers it, by its flag.
+type setting struct {
+	key   string
+	flag  string
+	usage string
+	// isBool marks settings whose flag may be given without a value.
+	isBool bool
+	set    func(c *Config, value string) error
+	get    func(c *Config) string
+}
+
+// settings lists every configuration value. The flag names match the flags the
+// applications accepted before configuration files existed.
+var settings = []setting{
+	stringSetting("log.level", "log-level", "Minimum level of logged records: debug, info, warn, error", func(c *Config) *string { return &c.Log.Level }),
+	stringSetting("log.format", "log-format", "Log output format: text, json", func(c *Config) *string { return &c.Log.Format }),
+	stringSetting("log.file", "log-file", "Write logs to this file instead of standard error", func(c *Config) *string { return &c.Log.File }),
+
+	stringSetting("quote.provider", "quote-provider", "Quote provider as name[:argument]: forismatic, file:path, builtin", func(c *Config) *string { return &c.Quote.Provider }),
+	intSetting("quote.category", "category", "Quote category", func(c *Config) *int { return &c.Quote.Category }),
+	intSetting("quote.retry_attempts", "quote-retry-attempts", "Attempts per request to the forismatic quote API", func(c *Config) *int { return &c.Quote.RetryAttempts }),
+
+	stringSetting("image.provider", "image-provider", "Image provider as name[:argument]: picsum, template:config.json, file:path, dir:path, generated", func(c *Config) *string { return &c.Image.Provider }),
+	intSetting("image.width", "width", "Image width", func(c *Config) *int { return &c.Image.Width }),
+	intSetting("image.height", "height", "Image height", func(c *Config) *int { return &c.Image.Height }),
+	listSetting("image.filters", "filters", "Image filters as a comma-separated list: grayscale, blur", func(c *Config) *[]string { return &c.Image.Filters }),
+	stringSetting("image.id", "image-id", "Catalog image ID to use instead of a random image", func(c *Config) *string { return &c.Image.ID }),
+	intSetting("image.retry_attempts", "image-retry-attempts", "Attempts per request to the picsum image API", func(c *Config) *int { return &c.Image.RetryAttempts }),
+	int64Setting("image.max_response_bytes", "image-max-response-bytes", "Largest image response read from the picsum image API, in bytes", func(c *Config) *int64 { return &c.Image.MaxResponseBytes }),
+	intSetting("image.max_pixels", "image-max-pixels", "Largest image accepted from the picsum image API, in pixels", func(c *Config) *int { return &c.Image.MaxPixels }),
+
+	boolSetting("card.enabled", "card", "Render the quote onto the image as a single quote card", func(c *Config) *bool { return &c.Card.Enabled }),
+	stringSetting("card.align", "align", "Quote card text alignment: left, center, right", func(c *Config) *string { return &c.Card.Align }),
+	stringSetting("card.valign", "valign", "Quote card text position: top, middle, bottom", func(c *Config) *string { return &c.Card.VAlign }),
+	stringSetting("card.background", "background", "Quote card text background: scrim, shadow, none", func(c *Config) *string { return &c.Card.Background }),
+	intSetting("card.margin", "margin", "Quote card margin in pixels between the text and the image edges", func(c *Config) *int { return &c.Card.Margin }),
+
+	intSetting("web.port", "port", "Port number for the web application", func(c *Config) *int { return &c.Web.Port }),
+	durationSetting("terminal.interval", "interval", "Show a new quote and image every interval as a slideshow, e.g. 30s", func(c *Config) *time.Duration { return &c.Terminal.Interval }),
+	stringSetting("terminal.color", "color", "Color mode of the rendered image: auto, truecolor, 256, 16, none", func(c *Config) *string { return &c.Terminal.Color }),
+	stringSetting("terminal.render", "render", "Characters or graphics protocol the image is drawn with: auto, blocks, half, quadrant, sextant, sixel, kitty, iterm2, ascii, braille", func(c *Config) *string { return &c.Terminal.Render }),
+	stringSetting("terminal.ramp", "ramp", "Characters of -render ascii, from dark to bright", func(c *Config) *string { return &c.Terminal.Ramp }),
+	boolSetting("terminal.edges", "edges", "Draw edges with line characters in -render ascii", func(c *Config) *bool { return &c.Terminal.Edges }),
+	stringSetting("terminal.dither", "dither", "Dithering of 256-color, 16-color, ASCII and Braille images: none, floyd-steinberg, atkinson, bayer", func(c *Config) *string { return &c.Terminal.Dither }),
+
+	boolSetting("resilience.degraded", "degraded", "Serve a placeholder image or fallback quote when one upstream API fails", func(c *Config) *bool { return &c.Resilience.Degraded }),
+	intSetting("resilience.breaker_threshold", "breaker-threshold", "Consecutive provider failures that open its circuit breaker, 0 disables circuit breakers", func(c *Config) *int { return &c.Resilience.BreakerThreshold }),
+	durationSetting("resilience.breaker_cooldown", "breaker-cooldown", "How long an open circuit breaker fails fast before retrying its provider", func(c *Config) *time.Duration { return &c.Resilience.BreakerCooldown }),
+	durationSetting("resilience.quote_hedge_delay", "quote-hedge-delay", "Send a second quote request when the first has not answered after this delay, 0 disables hedging", func(c *Config) *time.Duration { return &c.Resilience.QuoteHedgeDelay }),
+	durationSetting("resilience.image_hedge_delay", "image-hedge-delay", "Send a second image request when the first has not answered after this delay, 0 disables hedging", func(c *Config) *time.Duration { return &c.Resilience.ImageHedgeDelay }),
+
+	intSetting("pairing.prefetch", "prefetch", "Keep this many ready quote and image pairs per requested configuration, 0 disables prefetching", func(c *Config) *int { return &c.Pairing.Prefetch }),
+	stringSetting("pairing.mood", "mood", "Match the image to the quote's mood: filters, palette", func(c *Config) *string { return &c.Pairing.Mood }),
+	intSetting("pairing.mood_candidates", "mood-candidates", "Number of candidate images compared by -mood palette", func(c *Config) *int { return &c.Pairing.MoodCandidates }),
+	intSetting("pairing.keywords", "keywords", "Search for an image matching up to this many keywords of the quote when the image provider supports search, 0 disables search", func(c *Config) *int { return &c.Pairing.Keywords }),
+}
+
+// lookupSetting returns the setting with the given key.
+func lookupSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// Keys returns the keys of all settings in the order they are documented.
+func Keys() []string {
+	keys := make([]string, 0, len(settings))
+	for _, s := range settings {
+		keys = append(keys, s.key)
+	}
+	return keys
+}
+
+// EnvName returns the environment variable that sets key, e.g. TUCOWS_IMAGE_WIDTH for image.width.
+func EnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func stringSetting(key, flag, usage string, field func(*Config) *string) setting {
+	return setting{
+		key: key, flag: flag, usage: usage,
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+		get: func(c *Config) string { return *field(c) },
+	}
+}
+
+func intSetting(key, flag, usage string, field func(*Config) *int) setting {
+	return setting{
+		key: key, flag: flag, usage: usage,
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid integer %q", value)
+			}
+			*field(c) = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.Itoa(*field(c)) },
+	}
+}
+
+func int64Setting(key, flag, usage string, field func(*Config) *int64) setting {
+	return setting{
+		key: key, flag: flag, usage: usage,
+		set: func(c *Config, value string) error {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid integer %q", value)
+			}
+			*field(c) = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatInt(*field(c), 10) },
+	}
+}
+
+func boolSetting(key, flag, usage string, field func(*Config) *bool) setting {
+	return setting{
+		key: key, flag: flag, usage: usage, isBool: true,
+		set: func(c *Config, value string) error {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", value)
+			}
+			*field(c) = b
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatBool(*field(c)) },
+	}
+}
+
+func durationSetting(key, flag, usage string, field func(*Config) *time.Duration) setting {
+	return setting{
+		key: key, flag: flag, usage: usage,
+		set: func(c *Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid duration %q", value)
+			}
+			*field(c) = d
+			return nil
+		},
+		get: func(c *Config) string { return field(c).String() },
+	}
+}
+
+// listSetting parses a comma-separated list, dropping empty entries.
+func listSetting(key, flag, usage string, field func(*Config) *[]string) setting {
+	return setting{
+		key: key, flag: flag, usage: usage,
+		set: func(c *Config, value string) error {
+			var list []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			*field(c) = list
+			return nil
+		},
+		get: func(c *Config) string { return strings.Join(*field(c), ",") },
+	}
+}
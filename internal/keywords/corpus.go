@@ -0,0 +1,55 @@
+package keywords
+
+// corpus is a small bundled collection of quote-like sentences, one document per line.
+// Words frequent across it, such as "life" or "people", are weighted down as keywords.
+var corpus = `Life is what happens while you are busy making other plans.
+The purpose of life is to live it and to taste experience to the utmost.
+In the end it is not the years in your life that count, it is the life in your years.
+Life is short, so smile while you still have teeth.
+Love the life you live and live the life you love.
+The best time to plant a tree was twenty years ago, the second best time is now.
+Do what you can, with what you have, where you are.
+Believe you can and you are halfway there.
+People will forget what you said, but people will never forget how you made them feel.
+The only way to do great work is to love what you do.
+Success is not final, failure is not fatal, it is the courage to continue that counts.
+It always seems impossible until it is done.
+Happiness is not something ready made, it comes from your own actions.
+Be yourself, everyone else is already taken.
+The future belongs to those who believe in the beauty of their dreams.
+You miss every shot you do not take.
+Whatever you are, be a good one.
+Time you enjoy wasting is not wasted time.
+Well done is better than well said.
+The mind is everything, what you think you become.
+Knowledge speaks, but wisdom listens.
+The journey of a thousand miles begins with one step.
+Change your thoughts and you change your world.
+Nothing is impossible, the word itself says I am possible.
+Great minds discuss ideas, average minds discuss events, small minds discuss people.
+We become what we think about most of the time.
+Keep your face always toward the sunshine and shadows will fall behind you.
+The world is a book and those who do not travel read only one page.
+Act as if what you do makes a difference, because it does.
+What we think, we become.
+Happiness depends upon ourselves.
+A friend is someone who knows all about you and still loves you.
+Love all, trust a few, do wrong to none.
+Turn your wounds into wisdom.
+Everything you can imagine is real.
+Hope is a waking dream.
+The secret of getting ahead is getting started.
+Simplicity is the ultimate sophistication.
+Where there is love there is life.
+Stay hungry, stay foolish.
+Every moment is a fresh beginning.
+Dream big and dare to fail.
+The best way out is always through.
+Doubt kills more dreams than failure ever will.
+It does not matter how slowly you go as long as you do not stop.
+Life is really simple, but we insist on making it complicated.
+Our greatest glory is not in never falling, but in rising every time we fall.
+Kindness is a language which the deaf can hear and the blind can see.
+The people who are crazy enough to think they can change the world are the ones who do.
+Time is the most valuable thing a man can spend.
+`
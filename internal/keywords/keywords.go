@@ -0,0 +1,88 @@
+package keywords
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// MinWordLength is the length below which words are never keywords.
+const MinWordLength = 3
+
+// stopwords lists common English words that carry no meaning as search keywords.
+var stopwords = toSet(`a about above after again against all also always am an and any are as at be
+because been before being below between both but by can cannot could did do does doing done down
+during each even ever every few for from further get gets getting go goes had has have having he her
+here hers herself him himself his how i if in into is it its itself just let like made make makes many
+may me might more most much must my myself never no nor not now of off on once one only or other our
+ours ourselves out over own same say says said see she should so some still such than that the their
+theirs them themselves then there these they this those through to too under until up upon us very
+was we well were what when where which while who whom whose why will with within without would yet you
+your yours yourself yourselves`)
+
+// documentFrequencies counts in how many corpus documents each word appears, computed once.
+var documentFrequencies = sync.OnceValues(func() (map[string]int, int) {
+	frequencies := map[string]int{}
+	documents := strings.Split(strings.TrimSpace(corpus), "\n")
+	for _, document := range documents {
+		for word := range toSet(document) {
+			frequencies[word]++
+		}
+	}
+	return frequencies, len(documents)
+})
+
+// Extract returns up to n salient keywords of text, most salient first. Stopwords and
+// short words are dropped, and the rest are ranked by TF-IDF against the bundled corpus,
+// so words rare in everyday quotes rank above common ones. Ties keep the order of the text.
+func Extract(text string, n int) []string {
+	frequencies, documents := documentFrequencies()
+
+	var order []string
+	counts := map[string]int{}
+	for _, word := range words(text) {
+		if len(word) < MinWordLength || stopwords[word] {
+			continue
+		}
+		if counts[word] == 0 {
+			order = append(order, word)
+		}
+		counts[word]++
+	}
+
+	scores := make(map[string]float64, len(order))
+	for _, word := range order {
+		idf := math.Log(float64(documents+1)/float64(frequencies[word]+1)) + 1
+		scores[word] = float64(counts[word]) * idf
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	if len(order) > n {
+		order = order[:max(n, 0)]
+	}
+	return order
+}
+
+// words splits text into lowercase words, keeping apostrophes within words.
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '’'
+	})
+	for i, field := range fields {
+		fields[i] = strings.Trim(strings.ReplaceAll(field, "’", "'"), "'")
+	}
+	return fields
+}
+
+// toSet returns the set of words in text.
+func toSet(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range words(text) {
+		set[word] = true
+	}
+	return set
+}
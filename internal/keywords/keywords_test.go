@@ -0,0 +1,37 @@
+package keywords
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		n        int
+		expected []string
+	}{
+		{"drops stopwords", "The ocean is calm at sunset.", 3, []string{"ocean", "calm", "sunset"}},
+		{"ranks rare words first", "Life is a mountain and a river.", 2, []string{"mountain", "river"}},
+		{"repeated words rank higher", "Rain, rain and more rain over the harbor.", 2, []string{"rain", "harbor"}},
+		{"limits the number of keywords", "Forest, river, mountain, desert.", 2, []string{"forest", "river"}},
+		{"only stopwords", "It is what it is.", 3, nil},
+		{"no keywords requested", "Forest", 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Extract(tt.text, tt.n))
+		})
+	}
+}
+
+func TestExtract_IsDeterministic(t *testing.T) {
+	text := "A quiet forest, a quiet river and a quiet mountain at dawn."
+	first := Extract(text, 3)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, Extract(text, 3))
+	}
+}
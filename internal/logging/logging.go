@@ -0,0 +1,155 @@
+// Package logging creates the structured loggers used across the application and carries
+// request-scoped attributes, such as the request ID, through contexts.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	// FormatText and FormatJSON are the supported log output formats.
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Attribute keys shared by all packages.
+const (
+	RequestIDKey = "request_id"
+	ProviderKey  = "provider"
+	AttemptKey   = "attempt"
+	ErrorKey     = "error"
+)
+
+// New creates a logger writing records at or above level to w in the given format.
+// Attributes added to a context with WithAttrs are included in records logged with it.
+func New(w io.Writer, format string, level slog.Level) (*slog.Logger, error) {
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format {
+	case FormatText, "":
+		handler = slog.NewTextHandler(w, options)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected %s or %s", format, FormatText, FormatJSON)
+	}
+	return slog.New(NewContextHandler(handler)), nil
+}
+
+// Open creates a logger from configuration values. It appends to the file at path, or writes
+// to standard error when path is empty; close releases the file.
+func Open(path, format, levelName string) (logger *slog.Logger, close func() error, err error) {
+	level, err := ParseLevel(levelName)
+	if err != nil {
+		return nil, nil, err
+	}
+	var w io.Writer = os.Stderr
+	close = func() error { return nil }
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		w, close = file, file.Close
+	}
+	logger, err = New(w, format, level)
+	if err != nil {
+		close()
+		return nil, nil, err
+	}
+	return logger, close, nil
+}
+
+// ParseLevel parses a level name such as debug, info, warn or error.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// Discard returns a logger that drops every record.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+// OrDefault returns logger, or slog.Default() when it is nil.
+func OrDefault(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
+// Err returns the attribute reporting err.
+func Err(err error) slog.Attr {
+	return slog.Any(ErrorKey, err)
+}
+
+// NewRequestID returns a random identifier for correlating the records of one request.
+func NewRequestID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
+
+type attrsKey struct{}
+
+// WithAttrs returns a context carrying the given attributes in addition to those of ctx.
+// Arguments are key/value pairs or slog.Attr values, as for slog.Logger.With.
+func WithAttrs(ctx context.Context, args ...any) context.Context {
+	record := slog.Record{}
+	record.Add(args...)
+	attrs := append([]slog.Attr(nil), attrsFrom(ctx)...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+// attrsFrom returns the attributes carried by ctx.
+func attrsFrom(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// contextHandler adds the attributes carried by the context to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps handler so that records logged with a context include the
+// attributes added to it with WithAttrs.
+func NewContextHandler(handler slog.Handler) slog.Handler {
+	return contextHandler{handler}
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := attrsFrom(ctx); len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
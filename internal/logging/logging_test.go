@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_jsonIncludesContextAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, slog.LevelInfo)
+	assert.NoError(t, err)
+
+	ctx := WithAttrs(context.Background(), RequestIDKey, "abc123")
+	ctx = WithAttrs(ctx, slog.Int(AttemptKey, 2))
+	logger.With(ProviderKey, "picsum").WarnContext(ctx, "Image request failed", Err(errors.New("timeout")))
+	logger.DebugContext(ctx, "Dropped below the level")
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "Image request failed", record["msg"])
+	assert.Equal(t, "abc123", record[RequestIDKey])
+	assert.Equal(t, "picsum", record[ProviderKey])
+	assert.Equal(t, float64(2), record[AttemptKey])
+	assert.Equal(t, "timeout", record[ErrorKey])
+}
+
+func TestNew_text(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatText, slog.LevelDebug)
+	assert.NoError(t, err)
+
+	logger.DebugContext(WithAttrs(context.Background(), RequestIDKey, "abc123"), "Parsed request")
+	assert.Contains(t, buf.String(), `level=DEBUG msg="Parsed request" request_id=abc123`)
+
+	_, err = New(&buf, "xml", slog.LevelInfo)
+	assert.ErrorContains(t, err, `unknown log format "xml"`)
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError} {
+		level, err := ParseLevel(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, level)
+	}
+
+	_, err := ParseLevel("loud")
+	assert.ErrorContains(t, err, `unknown log level "loud"`)
+}
+
+func TestOpen_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, closeLog, err := Open(path, FormatText, "info")
+	assert.NoError(t, err)
+	logger.Info("Starting terminal application")
+	assert.NoError(t, closeLog())
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(contents), `msg="Starting terminal application"`)
+}
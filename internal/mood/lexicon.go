@@ -0,0 +1,34 @@
+package mood
+
+// lexicon rates words from -3 (very negative) to 3 (very positive), in the spirit of AFINN.
+var lexicon = map[string]int{
+	"abandon": -2, "afraid": -2, "agony": -3, "alone": -2, "anger": -3, "angry": -3, "anxious": -2,
+	"ashamed": -2, "awful": -3, "bad": -3, "betray": -3, "bitter": -2, "blame": -2, "broken": -2,
+	"burden": -2, "cold": -1, "cruel": -3, "cry": -2, "dark": -2, "darkness": -2, "dead": -3,
+	"death": -2, "defeat": -2, "despair": -3, "destroy": -3, "die": -3, "dies": -3, "difficult": -1,
+	"disappoint": -2, "doubt": -1, "dread": -2, "empty": -1, "enemy": -2, "evil": -3, "fail": -2,
+	"failure": -2, "fear": -2, "fight": -1, "forget": -1, "grave": -2, "grief": -2, "guilt": -3,
+	"hard": -1, "harm": -2, "hate": -3, "hatred": -3, "hell": -4, "hopeless": -2, "hurt": -2,
+	"ignorance": -2, "ill": -2, "lonely": -2, "lose": -3, "loss": -3, "lost": -3, "misery": -3,
+	"mistake": -2, "mourn": -2, "pain": -2, "poor": -2, "regret": -2, "sad": -2, "sadness": -2,
+	"scared": -2, "shame": -2, "sick": -2, "sorrow": -2, "struggle": -2, "suffer": -2, "suffering": -2,
+	"tears": -2, "terrible": -3, "tragedy": -2, "trouble": -2, "ugly": -3, "war": -2, "weak": -2,
+	"worry": -3, "worse": -3, "worst": -3, "wound": -2, "wrong": -2,
+
+	"accomplish": 2, "achieve": 2, "admire": 3, "alive": 1, "amazing": 4, "beautiful": 3, "best": 3,
+	"bless": 2, "bliss": 3, "bold": 2, "bright": 1, "brilliant": 4, "calm": 2, "celebrate": 3,
+	"cheer": 2, "comfort": 2, "courage": 2, "create": 1, "dream": 1, "dreams": 1, "easy": 1,
+	"enjoy": 2, "excellent": 3, "faith": 1, "free": 1, "freedom": 2, "friend": 1, "friends": 1,
+	"fun": 4, "generous": 2, "gentle": 2, "gift": 2, "glad": 3, "glory": 2, "good": 3, "grace": 1,
+	"grateful": 3, "great": 3, "happiness": 3, "happy": 3, "harmony": 2, "heal": 2, "hope": 2,
+	"inspire": 2, "joy": 3, "kind": 2, "kindness": 2, "laugh": 1, "light": 1, "love": 3, "lucky": 3,
+	"peace": 2, "perfect": 3, "play": 1, "pleasure": 3, "proud": 2, "shine": 2, "smile": 2,
+	"strength": 2, "strong": 2, "success": 2, "sun": 1, "sunshine": 2, "trust": 1, "warm": 1,
+	"win": 4, "wisdom": 1, "wonderful": 4,
+}
+
+// negations flip the rating of the word that follows them.
+var negations = map[string]bool{
+	"no": true, "not": true, "never": true, "nothing": true, "without": true,
+	"don't": true, "doesn't": true, "didn't": true, "isn't": true, "can't": true, "won't": true,
+}
@@ -0,0 +1,97 @@
+package mood
+
+import (
+	"image"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxRating is the largest absolute rating in the lexicon, used to normalise scores.
+	maxRating = 4
+	// paletteSamples is the number of pixels sampled along each axis to compute a palette.
+	paletteSamples = 32
+)
+
+// Score rates the sentiment of text from -1 (somber) to 1 (upbeat) using the bundled
+// lexicon. Text without rated words scores 0.
+func Score(text string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '’'
+	})
+
+	sum, rated := 0, 0
+	negated := false
+	for _, word := range words {
+		word = strings.ReplaceAll(word, "’", "'")
+		if negations[word] {
+			negated = true
+			continue
+		}
+		if rating, ok := lexicon[word]; ok {
+			if negated {
+				rating = -rating
+			}
+			sum += rating
+			rated++
+		}
+		negated = false
+	}
+	if rated == 0 {
+		return 0
+	}
+	return float64(sum) / float64(rated*maxRating)
+}
+
+// Palette summarises the colours of an image.
+type Palette struct {
+	// Brightness is the average luminance from 0 (black) to 1 (white).
+	Brightness float64
+	// Saturation is the average HSV saturation from 0 (grey) to 1 (vivid).
+	Saturation float64
+}
+
+// PaletteOf computes the palette of img from an evenly spaced grid of samples.
+func PaletteOf(img image.Image) Palette {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return Palette{}
+	}
+
+	var brightness, saturation float64
+	samples := 0
+	for sy := 0; sy < paletteSamples; sy++ {
+		for sx := 0; sx < paletteSamples; sx++ {
+			x := bounds.Min.X + sx*bounds.Dx()/paletteSamples
+			y := bounds.Min.Y + sy*bounds.Dy()/paletteSamples
+			r, g, b, _ := img.At(x, y).RGBA()
+			rf, gf, bf := float64(r)/0xffff, float64(g)/0xffff, float64(b)/0xffff
+
+			brightness += 0.299*rf + 0.587*gf + 0.114*bf
+			if high := math.Max(rf, math.Max(gf, bf)); high > 0 {
+				saturation += (high - math.Min(rf, math.Min(gf, bf))) / high
+			}
+			samples++
+		}
+	}
+	return Palette{Brightness: brightness / float64(samples), Saturation: saturation / float64(samples)}
+}
+
+// Mood maps the palette onto the sentiment scale: dark, muted images are somber
+// and bright, vivid images are upbeat.
+func (p Palette) Mood() float64 {
+	return math.Max(-1, math.Min(1, p.Brightness+p.Saturation-1))
+}
+
+// BestMatch returns the index of the image whose palette mood is closest to score.
+// Ties go to the earliest image, so the choice is deterministic. It returns -1 for no images.
+func BestMatch(score float64, images []image.Image) int {
+	best, bestDistance := -1, math.Inf(1)
+	for i, img := range images {
+		if distance := math.Abs(PaletteOf(img).Mood() - score); distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return best
+}
@@ -0,0 +1,64 @@
+package mood
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func solidImage(c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	draw.Draw(img, img.Bounds(), &image.Uniform{c}, image.Point{}, draw.Src)
+	return img
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		check func(float64) bool
+	}{
+		{"positive", "Happiness is a warm smile shared with friends.", func(s float64) bool { return s > 0.3 }},
+		{"negative", "Grief and despair follow every loss.", func(s float64) bool { return s < -0.3 }},
+		{"neutral", "The train leaves at noon.", func(s float64) bool { return s == 0 }},
+		{"negated", "I am not happy.", func(s float64) bool { return s < 0 }},
+		{"curly apostrophe", "Don’t worry.", func(s float64) bool { return s > 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := Score(tt.text)
+			assert.True(t, tt.check(score), "Unexpected score %.2f", score)
+			assert.GreaterOrEqual(t, score, -1.0)
+			assert.LessOrEqual(t, score, 1.0)
+		})
+	}
+}
+
+func TestPaletteOf(t *testing.T) {
+	white := PaletteOf(solidImage(color.White))
+	assert.InDelta(t, 1, white.Brightness, 0.01)
+	assert.InDelta(t, 0, white.Saturation, 0.01)
+
+	red := PaletteOf(solidImage(color.RGBA{R: 255, A: 255}))
+	assert.InDelta(t, 0.299, red.Brightness, 0.01)
+	assert.InDelta(t, 1, red.Saturation, 0.01)
+
+	assert.Equal(t, Palette{}, PaletteOf(image.NewRGBA(image.Rectangle{})))
+}
+
+func TestBestMatch(t *testing.T) {
+	images := []image.Image{
+		solidImage(color.RGBA{R: 255, G: 220, B: 40, A: 255}),
+		solidImage(color.RGBA{R: 30, G: 30, B: 40, A: 255}),
+		solidImage(color.RGBA{R: 128, G: 128, B: 128, A: 255}),
+	}
+
+	assert.Equal(t, 0, BestMatch(0.8, images), "Expected the bright, vivid image for an upbeat quote")
+	assert.Equal(t, 1, BestMatch(-0.8, images), "Expected the dark, muted image for a somber quote")
+	assert.Equal(t, 0, BestMatch(-0.5, images[2:]), "Expected the only image to match")
+	assert.Equal(t, -1, BestMatch(0, nil))
+}
@@ -0,0 +1,138 @@
+// Package shared defines the error taxonomy shared by the providers, the facade and the
+// applications, so that callers can tell an invalid request from an upstream outage.
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/avast/retry-go"
+)
+
+// Error kinds. Match them with errors.Is; use Kind to classify an error.
+var (
+	// ErrInvalidInput reports a request or configuration that can never succeed as given.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrUpstreamUnavailable reports an upstream API that could not be reached or answered with a server error.
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrUpstreamBadResponse reports an upstream response that was unexpected or exceeded the configured limits.
+	ErrUpstreamBadResponse = errors.New("upstream bad response")
+	// ErrDecode reports an upstream response body that could not be decoded.
+	ErrDecode = errors.New("decode failure")
+	// ErrTimeout reports a request that did not finish before its deadline.
+	ErrTimeout = errors.New("timeout")
+	// ErrRateLimited reports an upstream API that rejected the request as too frequent.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// kinds lists the error kinds in the order Kind checks them, with their codes.
+var kinds = []struct {
+	kind error
+	code string
+}{
+	{ErrInvalidInput, "invalid_input"},
+	{ErrRateLimited, "rate_limited"},
+	{ErrTimeout, "timeout"},
+	{ErrUpstreamUnavailable, "upstream_unavailable"},
+	{ErrUpstreamBadResponse, "upstream_bad_response"},
+	{ErrDecode, "decode_failure"},
+}
+
+// CodeInternal is the code of errors without a kind.
+const CodeInternal = "internal"
+
+// Error classifies Err with one of the error kinds.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns both the kind and the underlying error, so that errors.Is and errors.As match either.
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Wrap classifies err with kind. It returns nil when err is nil.
+func Wrap(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// InvalidInput formats an error, as fmt.Errorf does, and classifies it as ErrInvalidInput.
+func InvalidInput(format string, args ...any) error {
+	return Wrap(ErrInvalidInput, fmt.Errorf(format, args...))
+}
+
+// Kind returns the kind of err, or nil when it has none. An error matching several kinds
+// is reported as the first of: invalid input, rate limited, timeout, upstream unavailable,
+// upstream bad response and decode failure. Exceeded context deadlines are timeouts.
+func Kind(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.kind) {
+			return k.kind
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return nil
+}
+
+// Code returns a stable snake_case code for the kind of err, e.g. "upstream_unavailable",
+// or CodeInternal when it has none.
+func Code(err error) string {
+	kind := Kind(err)
+	for _, k := range kinds {
+		if k.kind == kind {
+			return k.code
+		}
+	}
+	return CodeInternal
+}
+
+// RequestError classifies an error returned by an HTTP client: timeouts as ErrTimeout and
+// any other failure to get a response as ErrUpstreamUnavailable.
+func RequestError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return Wrap(ErrTimeout, err)
+	}
+	return Wrap(ErrUpstreamUnavailable, err)
+}
+
+// StatusError classifies an unexpected HTTP status code of an upstream response:
+// 429 as ErrRateLimited, server errors as ErrUpstreamUnavailable and anything else
+// as ErrUpstreamBadResponse.
+func StatusError(statusCode int) error {
+	err := fmt.Errorf("received non-200 status code: %d", statusCode)
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return Wrap(ErrRateLimited, err)
+	case statusCode >= 500:
+		return Wrap(ErrUpstreamUnavailable, err)
+	default:
+		return Wrap(ErrUpstreamBadResponse, err)
+	}
+}
+
+// FlattenRetryError turns a retry.Error into a joined error so that the individual
+// attempt failures, and their kinds, can be matched with errors.Is and errors.As.
+func FlattenRetryError(err error) error {
+	var retryErr retry.Error
+	if errors.As(err, &retryErr) {
+		return errors.Join(retryErr.WrappedErrors()...)
+	}
+	return err
+}
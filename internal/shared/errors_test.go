@@ -0,0 +1,63 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/avast/retry-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("failed to get quote: %w", Wrap(ErrUpstreamUnavailable, cause))
+
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "failed to get quote: connection refused", err.Error())
+	assert.NoError(t, Wrap(ErrTimeout, nil))
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+		code     string
+	}{
+		{"nil", nil, nil, CodeInternal},
+		{"unclassified", errors.New("unexpected"), nil, CodeInternal},
+		{"invalid input", InvalidInput("unknown image filter %q", "sepia"), ErrInvalidInput, "invalid_input"},
+		{"deadline", fmt.Errorf("fetch: %w", context.DeadlineExceeded), ErrTimeout, "timeout"},
+		{"status", StatusError(http.StatusTooManyRequests), ErrRateLimited, "rate_limited"},
+		{"precedence", errors.Join(Wrap(ErrDecode, errors.New("bad JSON")), StatusError(http.StatusServiceUnavailable)), ErrUpstreamUnavailable, "upstream_unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Kind(tt.err))
+			assert.Equal(t, tt.code, Code(tt.err))
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	assert.ErrorIs(t, StatusError(http.StatusInternalServerError), ErrUpstreamUnavailable)
+	assert.ErrorIs(t, StatusError(http.StatusTooManyRequests), ErrRateLimited)
+	assert.ErrorIs(t, StatusError(http.StatusNotFound), ErrUpstreamBadResponse)
+	assert.EqualError(t, StatusError(http.StatusNotFound), "received non-200 status code: 404")
+}
+
+func TestRequestError(t *testing.T) {
+	assert.ErrorIs(t, RequestError(fmt.Errorf("get: %w", context.DeadlineExceeded)), ErrTimeout)
+	assert.ErrorIs(t, RequestError(errors.New("no such host")), ErrUpstreamUnavailable)
+}
+
+func TestFlattenRetryError(t *testing.T) {
+	err := FlattenRetryError(retry.Error{StatusError(http.StatusBadGateway), Wrap(ErrDecode, errors.New("bad JSON"))})
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	assert.ErrorIs(t, err, ErrDecode)
+}
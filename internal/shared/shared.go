@@ -1,8 +0,0 @@
-// shared/logging.go
-package shared
-
-const (
-	LogLevelInfo    = "INFO"
-	LogLevelWarning = "WARNING"
-	LogLevelError   = "ERROR"
-)
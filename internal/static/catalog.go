@@ -0,0 +1,72 @@
+package static
+
+var CatalogTemplate = `
+<style>
+body {
+	font-family: Arial, sans-serif;
+	background-color: #f4f4f4;
+	margin: 0;
+	padding: 20px;
+}
+h1 {
+	color: #333333;
+	text-align: center;
+}
+.grid {
+	display: flex;
+	flex-wrap: wrap;
+	justify-content: center;
+	gap: 16px;
+}
+.card {
+	background-color: #ffffff;
+	box-shadow: 0 2px 4px rgba(0, 0, 0, 0.1);
+	border-radius: 8px;
+	padding: 10px;
+	text-align: center;
+	width: 200px;
+}
+.card img {
+	border-radius: 4px;
+}
+.card p {
+	color: #666666;
+	font-size: 14px;
+	margin: 6px 0;
+}
+.pager {
+	text-align: center;
+	margin-top: 20px;
+}
+a {
+	color: #3366cc;
+}
+</style>
+
+<body>
+    <h1>Image Catalog</h1>
+    <form class="pager" method="get" action="/catalog">
+        <input type="text" name="author" placeholder="Author" value="{{ .Author }}">
+        <input type="hidden" name="limit" value="{{ .Limit }}">
+        <button type="submit">Filter</button>
+    </form>
+    <div class="grid">
+        {{ range .Images }}
+        <div class="card">
+            <img src="{{ .ThumbnailURL }}" alt="Image {{ .ID }}" width="200" height="150">
+            <p>{{ .Author }}</p>
+            <p>{{ .Width }}x{{ .Height }}</p>
+            <a href="/?image_id={{ .ID }}">Pin for quote</a>
+        </div>
+        {{ else }}
+        <p>No images found.</p>
+        {{ end }}
+    </div>
+    <div class="pager">
+        {{ if gt .Page 1 }}<a href="/catalog?page={{ .PrevPage }}&limit={{ .Limit }}&author={{ .Author }}">Previous</a>{{ end }}
+        <span>Page {{ .Page }}</span>
+        <a href="/catalog?page={{ .NextPage }}&limit={{ .Limit }}&author={{ .Author }}">Next</a>
+    </div>
+</body>
+</html>
+`
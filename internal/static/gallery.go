@@ -0,0 +1,64 @@
+package static
+
+var GalleryTemplate = `
+<style>
+body {
+	font-family: Arial, sans-serif;
+	background-color: #f4f4f4;
+	margin: 0;
+	padding: 20px;
+}
+h1 {
+	color: #333333;
+	text-align: center;
+}
+.grid {
+	display: flex;
+	flex-wrap: wrap;
+	justify-content: center;
+	gap: 16px;
+}
+.card {
+	background-color: #ffffff;
+	box-shadow: 0 2px 4px rgba(0, 0, 0, 0.1);
+	border-radius: 8px;
+	padding: 10px;
+	text-align: center;
+	max-width: 320px;
+}
+.card img {
+	max-width: 100%;
+	border-radius: 4px;
+}
+.card p {
+	color: #666666;
+	font-size: 16px;
+	line-height: 1.4;
+}
+.card .author {
+	font-style: italic;
+	font-size: 14px;
+}
+.card .error {
+	color: #aa3333;
+}
+</style>
+
+<body>
+    <h1>Random Text and Image Gallery</h1>
+    <div class="grid">
+        {{ range .Items }}
+        <div class="card">
+            {{ if .Error }}
+            <p class="error">{{ .Error }}</p>
+            {{ else }}
+            <img src="{{ .Image }}" alt="Random Image">
+            {{ if .Text }}<p>{{ .Text }}</p>{{ end }}
+            {{ if .Author }}<p class="author">{{ .Author }}</p>{{ end }}
+            {{ end }}
+        </div>
+        {{ end }}
+    </div>
+</body>
+</html>
+`
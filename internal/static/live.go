@@ -0,0 +1,61 @@
+package static
+
+var LiveTemplate = `
+<style>
+body {
+	font-family: Arial, sans-serif;
+	background-color: #f4f4f4;
+	text-align: center;
+	margin: 0;
+	padding: 0;
+}
+.container {
+	max-width: 800px;
+	margin: 50px auto;
+	padding: 20px;
+	background-color: #ffffff;
+	box-shadow: 0 2px 4px rgba(0, 0, 0, 0.1);
+	border-radius: 8px;
+}
+img {
+	max-width: 100%;
+	border-radius: 4px;
+}
+p {
+	color: #666666;
+	font-size: 18px;
+	line-height: 1.6;
+}
+.author {
+	font-style: italic;
+	font-size: 16px;
+}
+.error {
+	color: #aa3333;
+}
+</style>
+
+<body>
+    <div class="container">
+        <img id="image" alt="Random Image" hidden>
+        <p id="text">Waiting for the first quote...</p>
+        <p id="author" class="author"></p>
+    </div>
+    <script>
+    const events = new EventSource("/live/events" + window.location.search);
+    events.onmessage = (event) => {
+        const item = JSON.parse(event.data);
+        const text = document.getElementById("text");
+        text.className = item.error ? "error" : "";
+        text.textContent = item.error || item.text || "";
+        document.getElementById("author").textContent = item.author || "";
+        const image = document.getElementById("image");
+        image.hidden = !item.image;
+        if (item.image) {
+            image.src = item.image;
+        }
+    };
+    </script>
+</body>
+</html>
+`
@@ -0,0 +1,342 @@
+package tucows
+
+import (
+	"context"
+	"image"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api"
+	"github.com/ramyad/tucows/internal/api/breaker"
+	"github.com/ramyad/tucows/internal/api/facade"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/api/registry"
+	"github.com/ramyad/tucows/internal/card"
+	"github.com/ramyad/tucows/internal/shared"
+)
+
+const (
+	// MaxBatchSize is the largest number of pairs FetchBatch returns at once.
+	MaxBatchSize = facade.MaxBatchSize
+	// DefaultSubscribeInterval is used by Subscribe when no positive interval is given.
+	DefaultSubscribeInterval = facade.DefaultSubscribeInterval
+)
+
+// QuoteFetchError is returned when the quote provider fails to return a quote.
+type QuoteFetchError = facade.QuoteFetchError
+
+// ImageFetchError is returned when the image provider fails to return an image.
+type ImageFetchError = facade.ImageFetchError
+
+// CircuitOpenError is returned while a provider's circuit breaker is open.
+type CircuitOpenError = breaker.OpenError
+
+// Error kinds classify the errors returned by the Client; match them with errors.Is.
+var (
+	ErrInvalidInput        = shared.ErrInvalidInput
+	ErrUpstreamUnavailable = shared.ErrUpstreamUnavailable
+	ErrUpstreamBadResponse = shared.ErrUpstreamBadResponse
+	ErrDecode              = shared.ErrDecode
+	ErrTimeout             = shared.ErrTimeout
+	ErrRateLimited         = shared.ErrRateLimited
+)
+
+// Request describes the quote/image pair to fetch. Zero Width and Height select the
+// default image size; Filters accepts "grayscale" and "blur".
+type Request struct {
+	QuoteKey int
+	Width    int
+	Height   int
+	Filters  []string
+	Seed     string
+	ImageID  string
+}
+
+// Pair is a quote and the image fetched for it. In the results of FetchBatch and
+// Subscribe, Err reports why a single pair could not be fetched.
+type Pair struct {
+	Quote  string
+	Author string
+	Image  image.Image
+	Err    error
+}
+
+// CardStyle describes the quote card layout. Empty fields select the defaults.
+type CardStyle struct {
+	// Align is the text alignment: left, center or right.
+	Align string
+	// VAlign is the text position: top, middle or bottom.
+	VAlign string
+	// Background is the text background: scrim, shadow or none.
+	Background string
+	// Margin is the distance in pixels between the text and the image edges. Zero selects
+	// the default margin; negative margins are rejected.
+	Margin int
+}
+
+// Client fetches quote/image pairs. It is safe for concurrent use.
+type Client struct {
+	api api.API
+}
+
+// config collects the options passed to New.
+type config struct {
+	quoteProvider    QuoteProvider
+	imageProvider    ImageProvider
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	logger           *slog.Logger
+	facadeOptions    []facade.Option
+	// quoteBaseURL, imageBaseURL and the retry attempts tune the default providers.
+	quoteBaseURL       string
+	imageBaseURL       string
+	quoteRetryAttempts int
+	imageRetryAttempts int
+}
+
+// Option configures optional behaviour of the Client.
+type Option func(*config)
+
+// WithQuoteProvider sets the quote provider. It defaults to DefaultQuoteProvider.
+func WithQuoteProvider(provider QuoteProvider) Option {
+	return func(c *config) {
+		c.quoteProvider = provider
+	}
+}
+
+// WithImageProvider sets the image provider. It defaults to DefaultImageProvider.
+func WithImageProvider(provider ImageProvider) Option {
+	return func(c *config) {
+		c.imageProvider = provider
+	}
+}
+
+// WithBaseURLs points the default quote and image providers at other servers, such as a
+// mirror or a test server. An empty URL keeps the public service. Providers set with
+// WithQuoteProvider or WithImageProvider are not affected.
+func WithBaseURLs(quote, image string) Option {
+	return func(c *config) {
+		c.quoteBaseURL = quote
+		c.imageBaseURL = image
+	}
+}
+
+// WithRetryAttempts sets how many times the default quote and image providers try a
+// request before giving up. Zero keeps the default number of attempts.
+func WithRetryAttempts(quote, image int) Option {
+	return func(c *config) {
+		c.quoteRetryAttempts = quote
+		c.imageRetryAttempts = image
+	}
+}
+
+// WithDegradedMode returns a fallback quote or a placeholder image when only one of the
+// providers fails, instead of failing the whole pair.
+func WithDegradedMode() Option {
+	return func(c *config) {
+		c.facadeOptions = append(c.facadeOptions, facade.WithDegradedMode())
+	}
+}
+
+// WithFallbackQuote sets the quote returned in degraded mode when the quote provider fails.
+func WithFallbackQuote(quote Quote) Option {
+	return func(c *config) {
+		c.facadeOptions = append(c.facadeOptions, facade.WithFallbackQuote(quoteapi.Quote{Text: quote.Text, Author: quote.Author}))
+	}
+}
+
+// WithBatchConcurrency sets how many pairs FetchBatch fetches at the same time.
+func WithBatchConcurrency(n int) Option {
+	return func(c *config) {
+		c.facadeOptions = append(c.facadeOptions, facade.WithBatchConcurrency(n))
+	}
+}
+
+// WithCircuitBreakers wraps both providers in circuit breakers that open after threshold
+// consecutive failures and fail fast for cooldown before retrying.
+func WithCircuitBreakers(threshold int, cooldown time.Duration) Option {
+	return func(c *config) {
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
+	}
+}
+
+// WithLogger sets the logger for degraded results, hedged requests and circuit breaker
+// state changes. It defaults to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// WithHedgeDelays sends a second request to a provider that has not answered within its
+// delay and uses whichever answers first. A zero delay disables hedging for that provider.
+func WithHedgeDelays(quote, image time.Duration) Option {
+	return func(c *config) {
+		c.facadeOptions = append(c.facadeOptions, facade.WithQuoteHedgeDelay(quote), facade.WithImageHedgeDelay(image))
+	}
+}
+
+// WithPrefetchPool keeps up to size ready pairs per request, refilled in the background.
+func WithPrefetchPool(size int) Option {
+	return func(c *config) {
+		c.facadeOptions = append(c.facadeOptions, facade.WithPrefetchPool(size))
+	}
+}
+
+// WithMoodFilters applies grayscale and blur filters to images paired with somber quotes.
+func WithMoodFilters() Option {
+	return func(c *config) {
+		c.facadeOptions = append(c.facadeOptions, facade.WithMoodFilters())
+	}
+}
+
+// WithMoodPalette fetches candidates images per pair and keeps the one whose palette best
+// matches the mood of the quote.
+func WithMoodPalette(candidates int) Option {
+	return func(c *config) {
+		c.facadeOptions = append(c.facadeOptions, facade.WithMoodPalette(candidates))
+	}
+}
+
+// WithQuoteKeywords searches for an image matching up to n keywords of the quote when the
+// image provider supports search.
+func WithQuoteKeywords(n int) Option {
+	return func(c *config) {
+		c.facadeOptions = append(c.facadeOptions, facade.WithQuoteKeywords(n))
+	}
+}
+
+// New creates a Client configured with opts.
+func New(opts ...Option) *Client {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	// The default providers are tuned by the options and wrapped in circuit breakers too,
+	// so they are resolved here rather than left to the facade. Their specs are always valid.
+	defaults := registry.DefaultWithSettings(registry.Settings{
+		QuoteRetryAttempts: c.quoteRetryAttempts,
+		ImageRetryAttempts: c.imageRetryAttempts,
+		Logger:             c.logger,
+	})
+	if c.quoteProvider == nil {
+		provider, _ := defaults.QuoteProvider(defaultSpec(DefaultQuoteProvider, c.quoteBaseURL))
+		c.quoteProvider = builtinQuoteProvider{provider}
+	}
+	if c.imageProvider == nil {
+		provider, _ := defaults.ImageProvider(defaultSpec(DefaultImageProvider, c.imageBaseURL))
+		c.imageProvider = builtinImageProvider{provider}
+	}
+
+	quoteProvider := internalQuoteProvider(c.quoteProvider)
+	imageProvider := internalImageProvider(c.imageProvider)
+	if c.breakerThreshold > 0 {
+		quoteBreaker := breaker.NewBreakerBuilder("quote").WithLogger(c.logger).WithFailureThreshold(c.breakerThreshold).WithCooldown(c.breakerCooldown).Build()
+		quoteProvider = breaker.WrapQuoteProvider(quoteProvider, quoteBreaker)
+		imageBreaker := breaker.NewBreakerBuilder("image").WithLogger(c.logger).WithFailureThreshold(c.breakerThreshold).WithCooldown(c.breakerCooldown).Build()
+		imageProvider = breaker.WrapImageProvider(imageProvider, imageBreaker)
+	}
+	facadeOptions := []facade.Option{facade.WithLogger(c.logger), facade.WithQuoteProvider(quoteProvider), facade.WithImageProvider(imageProvider)}
+	facadeOptions = append(facadeOptions, c.facadeOptions...)
+
+	return &Client{api: facade.NewAPIFacade(facadeOptions...)}
+}
+
+// defaultSpec returns the spec of a default provider served from baseURL, when it is set.
+func defaultSpec(spec, baseURL string) string {
+	if baseURL == "" {
+		return spec
+	}
+	return spec + ":" + baseURL
+}
+
+// Fetch fetches a random quote and a random image for req concurrently.
+func (c *Client) Fetch(ctx context.Context, req Request) (Pair, error) {
+	pairRequest, err := req.pairRequest()
+	if err != nil {
+		return Pair{}, err
+	}
+	result, err := c.api.GetRandomQuoteWithImage(ctx, pairRequest)
+	if err != nil {
+		return Pair{}, err
+	}
+	return Pair{Quote: result.Quote, Author: result.Author, Image: result.Image}, nil
+}
+
+// FetchCard fetches a pair for req and renders the quote onto the image.
+func (c *Client) FetchCard(ctx context.Context, req Request, style CardStyle) (image.Image, error) {
+	pairRequest, err := req.pairRequest()
+	if err != nil {
+		return nil, err
+	}
+	margin := ""
+	if style.Margin != 0 {
+		margin = strconv.Itoa(style.Margin)
+	}
+	cardCnfgBldr, err := card.ParseCardConfig(style.Align, style.VAlign, style.Background, margin)
+	if err != nil {
+		return nil, shared.InvalidInput("invalid card style: %w", err)
+	}
+	return c.api.GetQuoteCard(ctx, pairRequest, cardCnfgBldr)
+}
+
+// FetchBatch fetches n distinct pairs for req, all sharing the deadline of ctx. Failures are
+// reported per pair in Pair.Err; the returned error is only set for an invalid request.
+func (c *Client) FetchBatch(ctx context.Context, n int, req Request) ([]Pair, error) {
+	pairRequest, err := req.pairRequest()
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.api.GetRandomQuotesWithImages(ctx, n, pairRequest)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]Pair, 0, len(results))
+	for _, result := range results {
+		pairs = append(pairs, newPair(result))
+	}
+	return pairs, nil
+}
+
+// Subscribe emits a fresh pair for req every interval until ctx is cancelled, after which the
+// returned channel is closed. When a fetch fails the last good pair is emitted again.
+func (c *Client) Subscribe(ctx context.Context, interval time.Duration, req Request) <-chan Pair {
+	pairs := make(chan Pair)
+	go func() {
+		defer close(pairs)
+
+		pairRequest, err := req.pairRequest()
+		if err != nil {
+			select {
+			case pairs <- Pair{Err: err}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		for result := range c.api.Subscribe(ctx, interval, pairRequest) {
+			select {
+			case pairs <- newPair(result):
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return pairs
+}
+
+// pairRequest validates the request and converts it for the internal pipeline.
+func (req Request) pairRequest() (api.PairRequest, error) {
+	builder := api.NewPairRequestBuilder().WithQuoteKey(req.QuoteKey).WithFilters(req.Filters).WithSeed(req.Seed).WithImageID(req.ImageID)
+	if req.Width != 0 {
+		builder.WithWidth(req.Width)
+	}
+	if req.Height != 0 {
+		builder.WithHeight(req.Height)
+	}
+	return builder.Build()
+}
+
+func newPair(pair api.Pair) Pair {
+	return Pair{Quote: pair.Quote, Author: pair.Author, Image: pair.Image, Err: pair.Err}
+}
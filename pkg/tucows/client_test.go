@@ -0,0 +1,167 @@
+package tucows
+
+import (
+	"context"
+	"errors"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedQuoteProvider always returns the same quote and records the last request.
+type fixedQuoteProvider struct {
+	quote Quote
+	err   error
+	last  QuoteRequest
+}
+
+func (p *fixedQuoteProvider) Quote(ctx context.Context, req QuoteRequest) (Quote, error) {
+	p.last = req
+	return p.quote, p.err
+}
+
+// solidImageProvider returns a blank image of the requested size.
+type solidImageProvider struct{}
+
+func (solidImageProvider) Image(ctx context.Context, req ImageRequest) (image.Image, error) {
+	return image.NewRGBA(image.Rect(0, 0, req.Width, req.Height)), nil
+}
+
+func TestClientFetch_customProviders(t *testing.T) {
+
+	quotes := &fixedQuoteProvider{quote: Quote{Text: "Stay curious.", Author: "Someone"}}
+	client := New(WithQuoteProvider(quotes), WithImageProvider(solidImageProvider{}))
+
+	pair, err := client.Fetch(context.Background(), Request{QuoteKey: 7, Width: 30, Height: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, "Stay curious.", pair.Quote)
+	assert.Equal(t, "Someone", pair.Author)
+	assert.Equal(t, image.Rect(0, 0, 30, 20), pair.Image.Bounds())
+	assert.Equal(t, QuoteRequest{Key: 7}, quotes.last)
+}
+
+func TestClientFetch_invalidRequest(t *testing.T) {
+
+	client := New(WithQuoteProvider(&fixedQuoteProvider{}), WithImageProvider(solidImageProvider{}))
+
+	_, err := client.Fetch(context.Background(), Request{Filters: []string{"sepia"}})
+	assert.ErrorContains(t, err, "unknown image filter")
+}
+
+func TestClientFetch_quoteFetchError(t *testing.T) {
+
+	quotes := &fixedQuoteProvider{err: errors.New("quote service down")}
+	client := New(WithQuoteProvider(quotes), WithImageProvider(solidImageProvider{}))
+
+	_, err := client.Fetch(context.Background(), Request{})
+	var quoteErr *QuoteFetchError
+	assert.True(t, errors.As(err, &quoteErr), "Expected QuoteFetchError")
+}
+
+func TestClientFetch_circuitBreakerOpens(t *testing.T) {
+
+	quotes := &fixedQuoteProvider{err: errors.New("quote service down")}
+	client := New(WithQuoteProvider(quotes), WithImageProvider(solidImageProvider{}), WithCircuitBreakers(1, time.Minute))
+
+	_, err := client.Fetch(context.Background(), Request{})
+	assert.Error(t, err)
+	_, err = client.Fetch(context.Background(), Request{})
+	var openErr *CircuitOpenError
+	assert.True(t, errors.As(err, &openErr), "Expected CircuitOpenError once the breaker is open")
+}
+
+// newNotFoundServer answers every request with 404 Not Found and counts them.
+func newNotFoundServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientFetch_circuitBreakersWrapDefaultProviders(t *testing.T) {
+	var quoteRequests, imageRequests atomic.Int32
+	quoteServer := newNotFoundServer(t, &quoteRequests)
+	imageServer := newNotFoundServer(t, &imageRequests)
+
+	// Degraded mode keeps one failing provider from cancelling the other, so both breakers open.
+	client := New(WithBaseURLs(quoteServer.URL, imageServer.URL), WithRetryAttempts(1, 1), WithCircuitBreakers(1, time.Minute), WithDegradedMode())
+
+	_, err := client.Fetch(context.Background(), Request{})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), quoteRequests.Load())
+	assert.Equal(t, int32(1), imageRequests.Load())
+
+	_, err = client.Fetch(context.Background(), Request{})
+	var openErr *CircuitOpenError
+	assert.True(t, errors.As(err, &openErr), "Expected CircuitOpenError once the breakers are open")
+	assert.Equal(t, int32(1), quoteRequests.Load(), "Expected the open quote breaker to fail fast without a request")
+	assert.Equal(t, int32(1), imageRequests.Load(), "Expected the open image breaker to fail fast without a request")
+}
+
+func TestClientFetchBatch(t *testing.T) {
+
+	quotes, err := NewQuoteProvider("builtin")
+	assert.NoError(t, err)
+	images, err := NewImageProvider("generated")
+	assert.NoError(t, err)
+	client := New(WithQuoteProvider(quotes), WithImageProvider(images))
+
+	pairs, err := client.FetchBatch(context.Background(), 3, Request{Width: 16, Height: 16})
+	assert.NoError(t, err)
+	assert.Len(t, pairs, 3)
+	for _, pair := range pairs {
+		assert.NoError(t, pair.Err)
+		assert.NotEmpty(t, pair.Quote)
+	}
+
+	_, err = client.FetchBatch(context.Background(), MaxBatchSize+1, Request{})
+	assert.Error(t, err)
+}
+
+func TestClientFetchCard(t *testing.T) {
+
+	client := New(WithQuoteProvider(&fixedQuoteProvider{quote: Quote{Text: "Stay curious."}}), WithImageProvider(solidImageProvider{}))
+
+	card, err := client.FetchCard(context.Background(), Request{Width: 200, Height: 100}, CardStyle{Align: "left", Margin: 8})
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 200, 100), card.Bounds())
+
+	_, err = client.FetchCard(context.Background(), Request{}, CardStyle{VAlign: "sideways"})
+	assert.ErrorContains(t, err, "invalid card style")
+	_, err = client.FetchCard(context.Background(), Request{}, CardStyle{Margin: -1})
+	assert.ErrorContains(t, err, "invalid margin")
+}
+
+func TestClientSubscribe(t *testing.T) {
+
+	client := New(WithQuoteProvider(&fixedQuoteProvider{quote: Quote{Text: "Stay curious."}}), WithImageProvider(solidImageProvider{}))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pairs := client.Subscribe(ctx, time.Hour, Request{Width: 10, Height: 10})
+	pair := <-pairs
+	assert.NoError(t, pair.Err)
+	assert.Equal(t, "Stay curious.", pair.Quote)
+
+	cancel()
+	for range pairs {
+	}
+}
+
+func TestNewImageProvider(t *testing.T) {
+
+	_, err := NewImageProvider("unknown")
+	assert.Error(t, err)
+
+	images, err := NewImageProvider("dir:" + t.TempDir())
+	assert.NoError(t, err)
+	_, ok := internalImageProvider(images).(imageapi.ImageSearcher)
+	assert.True(t, ok, "Built-in providers should keep their search capability")
+}
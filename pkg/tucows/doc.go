@@ -0,0 +1,19 @@
+// Package tucows is the public Go SDK for the quote and image pipeline behind the
+// terminal and web applications. It lets other services fetch quote/image pairs,
+// quote cards, galleries and live feeds without shelling out to the binaries.
+//
+// A Client is created with New and configured with functional options. Quotes and
+// images come from providers: the built-in ones are selected by spec with
+// NewQuoteProvider and NewImageProvider, and custom ones implement QuoteProvider
+// or ImageProvider.
+//
+//	client := tucows.New(tucows.WithImageProvider(images), tucows.WithDegradedMode())
+//	pair, err := client.Fetch(ctx, tucows.Request{Width: 800, Height: 600})
+//
+// The exported API of this package follows semantic versioning: within a major
+// version, identifiers are only added, never removed or changed incompatibly.
+// Version reports the API version implemented by this copy of the package.
+package tucows
+
+// Version is the semantic version of the public API of this package.
+const Version = "1.0.0"
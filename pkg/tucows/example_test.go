@@ -0,0 +1,57 @@
+package tucows_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ramyad/tucows/pkg/tucows"
+)
+
+// staticQuotes is a QuoteProvider implemented outside of the package.
+type staticQuotes struct{}
+
+func (staticQuotes) Quote(ctx context.Context, req tucows.QuoteRequest) (tucows.Quote, error) {
+	return tucows.Quote{Text: "Simplicity is prerequisite for reliability.", Author: "Edsger W. Dijkstra"}, nil
+}
+
+func ExampleClient_Fetch() {
+	images, err := tucows.NewImageProvider("generated")
+	if err != nil {
+		log.Fatal(err)
+	}
+	client := tucows.New(tucows.WithQuoteProvider(staticQuotes{}), tucows.WithImageProvider(images))
+
+	pair, err := client.Fetch(context.Background(), tucows.Request{Width: 80, Height: 60, Seed: "example"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s (%s)\n", pair.Quote, pair.Author)
+	fmt.Println(pair.Image.Bounds())
+	// Output:
+	// Simplicity is prerequisite for reliability. (Edsger W. Dijkstra)
+	// (0,0)-(80,60)
+}
+
+func ExampleClient_FetchCard() {
+	images, err := tucows.NewImageProvider("generated")
+	if err != nil {
+		log.Fatal(err)
+	}
+	client := tucows.New(tucows.WithQuoteProvider(staticQuotes{}), tucows.WithImageProvider(images))
+
+	card, err := client.FetchCard(context.Background(), tucows.Request{Width: 400, Height: 300}, tucows.CardStyle{Align: "center", VAlign: "bottom"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(card.Bounds())
+	// Output: (0,0)-(400,300)
+}
+
+func ExampleQuoteProviderNames() {
+	fmt.Println(tucows.QuoteProviderNames())
+	fmt.Println(tucows.ImageProviderNames())
+	// Output:
+	// [builtin file forismatic]
+	// [dir file generated picsum template]
+}
@@ -0,0 +1,160 @@
+package tucows
+
+import (
+	"context"
+	"image"
+
+	"github.com/ramyad/tucows/internal/api/imageapi"
+	"github.com/ramyad/tucows/internal/api/quoteapi"
+	"github.com/ramyad/tucows/internal/api/registry"
+)
+
+const (
+	// DefaultQuoteProvider is the spec of the quote provider used when none is configured.
+	DefaultQuoteProvider = registry.DefaultQuoteProvider
+	// DefaultImageProvider is the spec of the image provider used when none is configured.
+	DefaultImageProvider = registry.DefaultImageProvider
+)
+
+// Quote is a quote together with its author, which may be empty.
+type Quote struct {
+	Text   string
+	Author string
+}
+
+// QuoteRequest describes the quote a QuoteProvider should return.
+type QuoteRequest struct {
+	// Key selects the quote category.
+	Key int
+}
+
+// QuoteProvider fetches random quotes.
+type QuoteProvider interface {
+	Quote(ctx context.Context, req QuoteRequest) (Quote, error)
+}
+
+// ImageRequest describes the image an ImageProvider should return.
+type ImageRequest struct {
+	Width    int
+	Height   int
+	Filters  []string
+	Seed     string
+	ImageID  string
+	Keywords []string
+}
+
+// ImageProvider fetches random images.
+type ImageProvider interface {
+	Image(ctx context.Context, req ImageRequest) (image.Image, error)
+}
+
+// NewQuoteProvider creates a built-in quote provider from a spec of the form name[:argument]:
+// forismatic[:baseURL], file:path or builtin.
+func NewQuoteProvider(spec string) (QuoteProvider, error) {
+	provider, err := registry.Default().QuoteProvider(spec)
+	if err != nil {
+		return nil, err
+	}
+	return builtinQuoteProvider{provider}, nil
+}
+
+// NewImageProvider creates a built-in image provider from a spec of the form name[:argument]:
+// picsum[:baseURL], template:config.json, file:path, dir:path or generated.
+func NewImageProvider(spec string) (ImageProvider, error) {
+	provider, err := registry.Default().ImageProvider(spec)
+	if err != nil {
+		return nil, err
+	}
+	return builtinImageProvider{provider}, nil
+}
+
+// QuoteProviderNames returns the names of the built-in quote providers.
+func QuoteProviderNames() []string {
+	return registry.Default().QuoteProviderNames()
+}
+
+// ImageProviderNames returns the names of the built-in image providers.
+func ImageProviderNames() []string {
+	return registry.Default().ImageProviderNames()
+}
+
+// builtinQuoteProvider exposes an internal quote provider as a QuoteProvider.
+type builtinQuoteProvider struct {
+	inner quoteapi.QuoteProvider
+}
+
+func (p builtinQuoteProvider) Quote(ctx context.Context, req QuoteRequest) (Quote, error) {
+	qtCnfgBldr := quoteapi.NewQuoteConfigBuilder().WithKey(req.Key)
+	if authorProvider, ok := p.inner.(quoteapi.AuthorQuoteProvider); ok {
+		quote, err := authorProvider.GetRandomQuoteWithAuthor(ctx, qtCnfgBldr)
+		return Quote{Text: quote.Text, Author: quote.Author}, err
+	}
+	text, err := p.inner.GetRandomQuote(ctx, qtCnfgBldr)
+	return Quote{Text: text}, err
+}
+
+// builtinImageProvider exposes an internal image provider as an ImageProvider.
+type builtinImageProvider struct {
+	inner imageapi.ImageProvider
+}
+
+func (p builtinImageProvider) Image(ctx context.Context, req ImageRequest) (image.Image, error) {
+	imgCnfgBldr := imageapi.NewImageConfigBuilder().
+		WithWidth(req.Width).
+		WithHeight(req.Height).
+		WithFilters(req.Filters).
+		WithSeed(req.Seed).
+		WithImageID(req.ImageID).
+		WithKeywords(req.Keywords)
+	return p.inner.GetRandomImage(ctx, imgCnfgBldr)
+}
+
+// quoteProviderAdapter exposes a QuoteProvider to the internal pipeline.
+type quoteProviderAdapter struct {
+	provider QuoteProvider
+}
+
+func (a quoteProviderAdapter) GetRandomQuote(ctx context.Context, qtCnfgBldr *quoteapi.QuoteConfigBuilder) (string, error) {
+	quote, err := a.GetRandomQuoteWithAuthor(ctx, qtCnfgBldr)
+	return quote.Text, err
+}
+
+func (a quoteProviderAdapter) GetRandomQuoteWithAuthor(ctx context.Context, qtCnfgBldr *quoteapi.QuoteConfigBuilder) (quoteapi.Quote, error) {
+	quote, err := a.provider.Quote(ctx, QuoteRequest{Key: qtCnfgBldr.Build().Key})
+	return quoteapi.Quote{Text: quote.Text, Author: quote.Author}, err
+}
+
+// imageProviderAdapter exposes an ImageProvider to the internal pipeline.
+type imageProviderAdapter struct {
+	provider ImageProvider
+}
+
+func (a imageProviderAdapter) GetRandomImage(ctx context.Context, imgCnfgBldr *imageapi.ImageConfigBuilder) (image.Image, error) {
+	config := imgCnfgBldr.Build()
+	return a.provider.Image(ctx, ImageRequest{
+		Width:    config.Width,
+		Height:   config.Height,
+		Filters:  config.Filters,
+		Seed:     config.Seed,
+		ImageID:  config.ImageID,
+		Keywords: config.Keywords,
+	})
+}
+
+// internalQuoteProvider returns the internal form of provider. Built-in providers are
+// unwrapped so that their optional capabilities stay visible to the pipeline.
+func internalQuoteProvider(provider QuoteProvider) quoteapi.QuoteProvider {
+	if builtin, ok := provider.(builtinQuoteProvider); ok {
+		return builtin.inner
+	}
+	return quoteProviderAdapter{provider}
+}
+
+// internalImageProvider returns the internal form of provider. Built-in providers are
+// unwrapped so that keyword search stays available to the pipeline.
+func internalImageProvider(provider ImageProvider) imageapi.ImageProvider {
+	if builtin, ok := provider.(builtinImageProvider); ok {
+		return builtin.inner
+	}
+	return imageProviderAdapter{provider}
+}